@@ -0,0 +1,121 @@
+// Package grpclog 提供实现 grpclog.LoggerV2 及 grpclog.DepthLoggerV2 接口的日志对象,
+// 为避免引入 grpc 依赖, 本包不导入 grpc, 使用方式:
+//
+//	grpclog.SetLoggerV2(tgrpclog.New(tlog.Named("grpc")))
+package grpclog
+
+import (
+	"fmt"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+// grpc 的 Depth 方法经过 grpclog 包函数调用, 与 grpc 内置的 glogger 一致
+const depthSkip = 2
+
+type Option func(*Logger)
+
+// SetVerbosity 设置 V 方法的日志详细级别, 默认为 0
+func SetVerbosity(verbosity int) Option {
+	return func(p *Logger) {
+		p.verbosity = verbosity
+	}
+}
+
+// SetDepth 设置 Info, Warning 等非 Depth 方法跳过的调用层数,
+// 默认为 1, 即跳过 grpclog.Info 等包函数
+func SetDepth(depth int) Option {
+	return func(p *Logger) {
+		p.depth = depth
+	}
+}
+
+// Logger 是一个输出到 tlog 日志对象的 grpclog.LoggerV2
+type Logger struct {
+	logger    iface.Logger
+	verbosity int
+	depth     int
+}
+
+// New 构造 grpclog.LoggerV2 日志对象
+func New(logger iface.Logger, opts ...Option) *Logger {
+	p := &Logger{logger: logger, depth: 1}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *Logger) Info(args ...interface{}) {
+	p.logger.Print(p.depth+1, iface.INFO, args...)
+}
+
+func (p *Logger) Infoln(args ...interface{}) {
+	p.logger.Print(p.depth+1, iface.INFO, sprintln(args))
+}
+
+func (p *Logger) Infof(format string, args ...interface{}) {
+	p.logger.Printf(p.depth+1, iface.INFO, format, args...)
+}
+
+func (p *Logger) Warning(args ...interface{}) {
+	p.logger.Print(p.depth+1, iface.WARN, args...)
+}
+
+func (p *Logger) Warningln(args ...interface{}) {
+	p.logger.Print(p.depth+1, iface.WARN, sprintln(args))
+}
+
+func (p *Logger) Warningf(format string, args ...interface{}) {
+	p.logger.Printf(p.depth+1, iface.WARN, format, args...)
+}
+
+func (p *Logger) Error(args ...interface{}) {
+	p.logger.Print(p.depth+1, iface.ERROR, args...)
+}
+
+func (p *Logger) Errorln(args ...interface{}) {
+	p.logger.Print(p.depth+1, iface.ERROR, sprintln(args))
+}
+
+func (p *Logger) Errorf(format string, args ...interface{}) {
+	p.logger.Printf(p.depth+1, iface.ERROR, format, args...)
+}
+
+func (p *Logger) Fatal(args ...interface{}) {
+	p.logger.Print(p.depth+1, iface.FATAL, args...)
+}
+
+func (p *Logger) Fatalln(args ...interface{}) {
+	p.logger.Print(p.depth+1, iface.FATAL, sprintln(args))
+}
+
+func (p *Logger) Fatalf(format string, args ...interface{}) {
+	p.logger.Printf(p.depth+1, iface.FATAL, format, args...)
+}
+
+func (p *Logger) V(l int) bool {
+	return l <= p.verbosity
+}
+
+func (p *Logger) InfoDepth(depth int, args ...interface{}) {
+	p.logger.Print(depth+depthSkip, iface.INFO, sprintln(args))
+}
+
+func (p *Logger) WarningDepth(depth int, args ...interface{}) {
+	p.logger.Print(depth+depthSkip, iface.WARN, sprintln(args))
+}
+
+func (p *Logger) ErrorDepth(depth int, args ...interface{}) {
+	p.logger.Print(depth+depthSkip, iface.ERROR, sprintln(args))
+}
+
+func (p *Logger) FatalDepth(depth int, args ...interface{}) {
+	p.logger.Print(depth+depthSkip, iface.FATAL, sprintln(args))
+}
+
+// sprintln 按 fmt.Sprintln 格式化参数, 并去掉末尾的换行符
+func sprintln(args []interface{}) string {
+	s := fmt.Sprintln(args...)
+	return s[:len(s)-1]
+}
@@ -0,0 +1,77 @@
+package grpclog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ironzhang/tlog/zaplog/zlogger"
+)
+
+// grpcInfo, grpcWarningDepth 模拟 grpclog 包函数
+func grpcInfo(l *Logger, args ...interface{}) {
+	l.Info(args...)
+}
+
+func grpcWarningDepth(l *Logger, depth int, args ...interface{}) {
+	l.WarningDepth(depth, args...)
+}
+
+func newTestLogger(opts ...Option) (*Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return New(zlogger.New("", core, nil, zap.AddCaller()), opts...), logs
+}
+
+func TestLogger(t *testing.T) {
+	logger, logs := newTestLogger()
+
+	grpcInfo(logger, "info", 1)
+	grpcWarningDepth(logger, 0, "warning", 2)
+	logger.ErrorDepth(-1, "error", 3)
+
+	tests := []struct {
+		level   zapcore.Level
+		message string
+	}{
+		{level: zapcore.InfoLevel, message: "info1"},
+		{level: zapcore.WarnLevel, message: "warning 2"},
+		{level: zapcore.ErrorLevel, message: "error 3"},
+	}
+	entries := logs.AllUntimed()
+	if got, want := len(entries), len(tests); got != want {
+		t.Fatalf("entries: got %d, want %d", got, want)
+	}
+	for i, tt := range tests {
+		assert.Equal(t, tt.level, entries[i].Level, "%d: unexpected level", i)
+		assert.Equal(t, tt.message, entries[i].Message, "%d: unexpected message", i)
+		assert.Regexp(t, `grpclog_test.go`, entries[i].Caller.String(), "%d: unexpected caller", i)
+	}
+}
+
+func TestLoggerDirect(t *testing.T) {
+	logger, logs := newTestLogger(SetDepth(0))
+
+	logger.Infoln("infoln", 1)
+	logger.Warningf("warningf %d", 2)
+	logger.Error("error ", 3)
+
+	var messages []string
+	for _, e := range logs.AllUntimed() {
+		messages = append(messages, e.Message)
+		assert.Regexp(t, `grpclog_test.go`, e.Caller.String(), "unexpected caller")
+	}
+	assert.Equal(t, []string{"infoln 1", "warningf 2", "error 3"}, messages, "unexpected messages")
+}
+
+func TestLoggerV(t *testing.T) {
+	logger, _ := newTestLogger()
+	assert.True(t, logger.V(0))
+	assert.False(t, logger.V(1))
+
+	logger, _ = newTestLogger(SetVerbosity(2))
+	assert.True(t, logger.V(2))
+	assert.False(t, logger.V(3))
+}
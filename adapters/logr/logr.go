@@ -0,0 +1,71 @@
+// Package logr 提供输出到 tlog 日志对象的 logr.LogSink,
+// V(0) 输出为 INFO 级别, V(1) 及以上输出为 DEBUG 级别
+package logr
+
+import (
+	"github.com/go-logr/logr"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+// errorKey Error 方法输出错误的字段名
+const errorKey = "error"
+
+// LogSink 是一个输出到 tlog 日志对象的 logr.LogSink
+type LogSink struct {
+	logger iface.Logger
+	depth  int
+}
+
+var _ logr.CallDepthLogSink = (*LogSink)(nil)
+
+// NewLogSink 构造 logr.LogSink
+func NewLogSink(logger iface.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+// New 构造 logr.Logger
+func New(logger iface.Logger) logr.Logger {
+	return logr.New(NewLogSink(logger))
+}
+
+func (p *LogSink) Init(info logr.RuntimeInfo) {
+	p.depth += info.CallDepth
+}
+
+func (p *LogSink) Enabled(level int) bool {
+	if e, ok := p.logger.(iface.LevelEnabler); ok {
+		return e.Enabled(logLevel(level))
+	}
+	return true
+}
+
+func (p *LogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	p.logger.Printw(p.depth+1, logLevel(level), msg, keysAndValues...)
+}
+
+func (p *LogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	kvs := make([]interface{}, 0, len(keysAndValues)+2)
+	kvs = append(kvs, errorKey, err)
+	kvs = append(kvs, keysAndValues...)
+	p.logger.Printw(p.depth+1, iface.ERROR, msg, kvs...)
+}
+
+func (p *LogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &LogSink{logger: p.logger.WithArgs(keysAndValues...), depth: p.depth}
+}
+
+func (p *LogSink) WithName(name string) logr.LogSink {
+	return &LogSink{logger: p.logger.Named(name), depth: p.depth}
+}
+
+func (p *LogSink) WithCallDepth(depth int) logr.LogSink {
+	return &LogSink{logger: p.logger, depth: p.depth + depth}
+}
+
+func logLevel(level int) iface.Level {
+	if level > 0 {
+		return iface.DEBUG
+	}
+	return iface.INFO
+}
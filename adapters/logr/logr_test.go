@@ -0,0 +1,60 @@
+package logr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ironzhang/tlog/zaplog/zlogger"
+)
+
+func logHelper(logger logr.Logger, msg string) {
+	logger.WithCallDepth(1).Info(msg)
+}
+
+func TestLogSink(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := New(zlogger.New("", core, nil, zap.AddCaller()))
+
+	err := errors.New("EOF")
+	logger.Info("info", "k1", "v1")
+	logger.V(1).Info("debug")
+	logger.WithName("controller").WithValues("k1", "v1").Error(err, "error", "k2", 2)
+	logHelper(logger, "depth")
+
+	want := []observer.LoggedEntry{
+		{
+			Entry:   zapcore.Entry{Level: zapcore.InfoLevel, Message: "info"},
+			Context: []zapcore.Field{zap.String("k1", "v1")},
+		},
+		{
+			Entry:   zapcore.Entry{Level: zapcore.ErrorLevel, LoggerName: "controller", Message: "error"},
+			Context: []zapcore.Field{zap.String("k1", "v1"), zap.Error(err), zap.Int("k2", 2)},
+		},
+		{
+			Entry:   zapcore.Entry{Level: zapcore.InfoLevel, Message: "depth"},
+			Context: []zapcore.Field{},
+		},
+	}
+	entries := logs.AllUntimed()
+	if got, want := len(entries), len(want); got != want {
+		t.Fatalf("entries: got %d, want %d", got, want)
+	}
+	for i := range entries {
+		assert.Regexp(t, `logr_test.go`, entries[i].Caller.String(), "%d: unexpected caller", i)
+		entries[i].Caller = zapcore.EntryCaller{}
+	}
+	assert.Equal(t, want, entries, "unexpected log entries")
+}
+
+func TestLogSinkEnabled(t *testing.T) {
+	core, _ := observer.New(zapcore.InfoLevel)
+	logger := New(zlogger.New("", core, nil))
+	assert.True(t, logger.Enabled())
+	assert.False(t, logger.V(1).Enabled())
+}
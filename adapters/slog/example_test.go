@@ -0,0 +1,26 @@
+//go:build go1.21
+// +build go1.21
+
+package slog_test
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/ironzhang/tlog"
+	tslog "github.com/ironzhang/tlog/adapters/slog"
+	"github.com/ironzhang/tlog/zaplog"
+)
+
+func ExampleNewHandler() {
+	// 使用 slog 接口的代码输出到 tlog
+	slog.SetDefault(slog.New(tslog.NewHandler(zaplog.StdLogger())))
+	slog.Info("hello, world", "k1", "v1")
+}
+
+func ExampleNewLogger() {
+	// 使用 tlog 接口的代码输出到 slog
+	tlog.SetLogger(tslog.NewLogger(slog.NewJSONHandler(os.Stdout, nil)))
+	defer tlog.SetLogger(zaplog.StdLogger())
+	tlog.Infow("hello, world", "k1", "v1")
+}
@@ -0,0 +1,92 @@
+//go:build go1.21
+// +build go1.21
+
+// Package slog 提供 log/slog 与 tlog 之间的双向适配
+package slog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+// 日志对象未实现 iface.EntryPrinter 时, 按 slog.Logger 的输出方法经过 log 方法调用 Handler.Handle 计算调用位置
+const handlerDepth = 3
+
+// Handler 是一个将日志写入 tlog 日志对象的 slog.Handler,
+// 分组以 "." 连接为字段名前缀
+type Handler struct {
+	logger iface.Logger
+	prefix string
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler 构造 slog.Handler, 可通过 slog.New(NewHandler(logger)) 使用 slog 接口输出到 tlog
+func NewHandler(logger iface.Logger) *Handler {
+	return &Handler{logger: logger}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if e, ok := h.logger.(iface.LevelEnabler); ok {
+		return e.Enabled(LogLevel(level))
+	}
+	return true
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	var kvs []interface{}
+	if n := r.NumAttrs(); n > 0 {
+		kvs = make([]interface{}, 0, 2*n)
+		r.Attrs(func(a slog.Attr) bool {
+			kvs = appendAttr(kvs, h.prefix, a)
+			return true
+		})
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// 使用 Record 中的时间及调用位置, 被其他 Handler 包装或由 slog.NewRecord 构造时也能正确输出
+	if p, ok := h.logger.(iface.EntryPrinter); ok {
+		p.PrintEntry(ctx, r.Time, r.PC, LogLevel(r.Level), r.Message, kvs...)
+		return nil
+	}
+	h.logger.Printc(ctx, handlerDepth, LogLevel(r.Level), r.Message, kvs...)
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var kvs []interface{}
+	for _, a := range attrs {
+		kvs = appendAttr(kvs, h.prefix, a)
+	}
+	if len(kvs) <= 0 {
+		return h
+	}
+	return &Handler{logger: h.logger.WithArgs(kvs...), prefix: h.prefix}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{logger: h.logger, prefix: h.prefix + name + "."}
+}
+
+func appendAttr(kvs []interface{}, prefix string, a slog.Attr) []interface{} {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			kvs = appendAttr(kvs, prefix, ga)
+		}
+		return kvs
+	}
+	if a.Equal(slog.Attr{}) {
+		return kvs
+	}
+	return append(kvs, prefix+a.Key, a.Value.Any())
+}
@@ -0,0 +1,144 @@
+//go:build go1.21
+// +build go1.21
+
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zbase"
+	"github.com/ironzhang/tlog/zaplog/zlogger"
+)
+
+type tValuer struct{}
+
+func (tValuer) LogValue() slog.Value {
+	return slog.StringValue("resolved")
+}
+
+func newTestHandler(level iface.Level, opts ...zap.Option) (*Handler, *observer.ObservedLogs) {
+	core, logs := observer.New(zbase.ZapLevel(level))
+	return NewHandler(zlogger.New("", core, nil, opts...)), logs
+}
+
+func TestHandlerLevel(t *testing.T) {
+	h, logs := newTestHandler(iface.INFO)
+	logger := slog.New(h)
+
+	assert.False(t, h.Enabled(context.Background(), slog.LevelDebug))
+	assert.True(t, h.Enabled(context.Background(), slog.LevelInfo))
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+	logger.Log(context.Background(), slog.LevelError+4, "error+4")
+
+	var levels []zapcore.Level
+	for _, e := range logs.AllUntimed() {
+		levels = append(levels, e.Level)
+	}
+	want := []zapcore.Level{zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel, zapcore.ErrorLevel}
+	assert.Equal(t, want, levels, "unexpected levels")
+}
+
+func TestHandlerAttrs(t *testing.T) {
+	h, logs := newTestHandler(iface.DEBUG)
+	logger := slog.New(h)
+
+	logger.Info("attrs", "k1", "v1", "k2", 2, slog.Duration("d", time.Second), slog.Any("v", tValuer{}))
+	logger.Info("group", slog.Group("g", "k1", "v1", slog.Group("h", "k2", 2)), slog.Group("empty"))
+	logger.Info("inline", slog.Group("", "k1", "v1"), slog.Attr{})
+	logger.With("k1", "v1").WithGroup("g").With("k2", 2).Info("with", "k3", "v3")
+	logger.WithGroup("").WithGroup("g").WithGroup("h").Info("groups", "k1", "v1")
+
+	want := []observer.LoggedEntry{
+		{
+			Entry:   zapcore.Entry{Level: zapcore.InfoLevel, Message: "attrs"},
+			Context: []zapcore.Field{zap.String("k1", "v1"), zap.Int64("k2", 2), zap.Duration("d", time.Second), zap.String("v", "resolved")},
+		},
+		{
+			Entry:   zapcore.Entry{Level: zapcore.InfoLevel, Message: "group"},
+			Context: []zapcore.Field{zap.String("g.k1", "v1"), zap.Int64("g.h.k2", 2)},
+		},
+		{
+			Entry:   zapcore.Entry{Level: zapcore.InfoLevel, Message: "inline"},
+			Context: []zapcore.Field{zap.String("k1", "v1")},
+		},
+		{
+			Entry:   zapcore.Entry{Level: zapcore.InfoLevel, Message: "with"},
+			Context: []zapcore.Field{zap.String("k1", "v1"), zap.Int64("g.k2", 2), zap.String("g.k3", "v3")},
+		},
+		{
+			Entry:   zapcore.Entry{Level: zapcore.InfoLevel, Message: "groups"},
+			Context: []zapcore.Field{zap.String("g.h.k1", "v1")},
+		},
+	}
+	assert.Equal(t, want, logs.AllUntimed(), "unexpected log entries")
+}
+
+func TestHandlerContext(t *testing.T) {
+	h, logs := newTestHandler(iface.DEBUG)
+	logger := slog.New(h)
+
+	ctx := iface.ContextWithArgs(context.Background(), "request", "r1")
+	logger.InfoContext(ctx, "context", "k1", "v1")
+
+	want := []observer.LoggedEntry{
+		{
+			Entry:   zapcore.Entry{Level: zapcore.InfoLevel, Message: "context"},
+			Context: []zapcore.Field{zap.String("request", "r1"), zap.String("k1", "v1")},
+		},
+	}
+	assert.Equal(t, want, logs.AllUntimed(), "unexpected log entries")
+}
+
+func TestHandlerCaller(t *testing.T) {
+	h, logs := newTestHandler(iface.DEBUG, zap.AddCaller())
+
+	slog.New(h).Info("caller")
+	slog.New(h).With("k", "v").Log(context.Background(), slog.LevelInfo, "caller")
+
+	output := logs.AllUntimed()
+	assert.Equal(t, 2, len(output), "unexpected number of logs written out")
+	for _, e := range output {
+		assert.Regexp(t, `handler_test.go`, e.Caller.String(), "unexpected caller")
+	}
+}
+
+// tWrapHandler 包装另一个 Handler, 调用深度与 slog.Logger 直接调用时不同
+type tWrapHandler struct {
+	slog.Handler
+}
+
+func (h tWrapHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestHandlerRecord(t *testing.T) {
+	h, logs := newTestHandler(iface.DEBUG, zap.AddCaller())
+
+	_, _, line, _ := runtime.Caller(0)
+	slog.New(tWrapHandler{h}).Info("wrapped")
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	h.Handle(context.Background(), slog.NewRecord(now, slog.LevelWarn, "record", 0))
+
+	output := logs.All()
+	assert.Equal(t, 2, len(output), "unexpected number of logs written out")
+	assert.Equal(t, line+1, output[0].Caller.Line, "unexpected caller")
+	assert.False(t, output[0].Time.IsZero(), "unexpected time")
+	assert.False(t, output[1].Caller.Defined, "unexpected caller")
+	assert.Equal(t, now, output[1].Time, "unexpected time")
+	assert.Equal(t, zapcore.WarnLevel, output[1].Level, "unexpected level")
+}
@@ -0,0 +1,53 @@
+//go:build go1.21
+// +build go1.21
+
+package slog
+
+import (
+	"log/slog"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+// slog 没有 PANIC, FATAL 级别, 以 LevelError 之上的级别表示
+const (
+	LevelPanic = slog.LevelError + 4
+	LevelFatal = slog.LevelError + 8
+)
+
+// LogLevel 将 slog 日志级别转换为 tlog 日志级别, 高于 LevelError 的级别按 ERROR 处理
+func LogLevel(l slog.Level) iface.Level {
+	switch {
+	case l < slog.LevelInfo:
+		return iface.DEBUG
+	case l < slog.LevelWarn:
+		return iface.INFO
+	case l < slog.LevelError:
+		return iface.WARN
+	default:
+		return iface.ERROR
+	}
+}
+
+// SlogLevel 将 tlog 日志级别转换为 slog 日志级别
+func SlogLevel(l iface.Level) slog.Level {
+	switch l {
+	case iface.DEBUG:
+		return slog.LevelDebug
+	case iface.INFO:
+		return slog.LevelInfo
+	case iface.WARN:
+		return slog.LevelWarn
+	case iface.ERROR:
+		return slog.LevelError
+	case iface.PANIC:
+		return LevelPanic
+	case iface.FATAL:
+		return LevelFatal
+	default:
+		if l < iface.DEBUG {
+			return slog.LevelDebug
+		}
+		return LevelFatal
+	}
+}
@@ -0,0 +1,311 @@
+//go:build go1.21
+// +build go1.21
+
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+// nameKey 日志对象名称的字段名, 与 zaplog 默认的 NameKey 一致
+const nameKey = "logger"
+
+// Logger 是一个将日志写入 slog.Handler 的 tlog 日志对象,
+// 可通过 tlog.SetLogger(NewLogger(handler)) 使用 tlog 接口输出到 slog
+type Logger struct {
+	handler slog.Handler
+	name    string
+	ctx     context.Context
+	exit    func(code int)
+	fatal   []func()
+}
+
+var _ iface.Logger = (*Logger)(nil)
+
+type Option func(*Logger)
+
+// SetExitFunc 设置输出 FATAL 级别日志后调用的退出函数, 默认为 os.Exit;
+// 退出函数返回时 Fatal 等方法正常返回, 测试中可用于拦截退出
+func SetExitFunc(exit func(code int)) Option {
+	return func(p *Logger) {
+		p.exit = exit
+	}
+}
+
+// OnFatal 注册输出 FATAL 级别日志后, 退出前调用的函数, 按注册顺序调用
+func OnFatal(f func()) Option {
+	return func(p *Logger) {
+		p.fatal = append(p.fatal, f)
+	}
+}
+
+// NewLogger 构造 tlog 日志对象
+func NewLogger(h slog.Handler, opts ...Option) *Logger {
+	p := &Logger{handler: h, ctx: context.Background(), exit: os.Exit}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *Logger) clone() *Logger {
+	c := *p
+	return &c
+}
+
+func (p *Logger) Named(name string) iface.Logger {
+	if len(name) <= 0 {
+		return p
+	}
+	c := p.clone()
+	if c.name == "" {
+		c.name = name
+	} else {
+		c.name = c.name + "." + name
+	}
+	return c
+}
+
+func (p *Logger) WithArgs(args ...interface{}) iface.Logger {
+	if len(args) <= 0 {
+		return p
+	}
+	c := p.clone()
+	c.handler = c.handler.WithAttrs(argsToAttrs(args))
+	return c
+}
+
+func (p *Logger) WithContext(ctx context.Context) iface.Logger {
+	return p.withContext(ctx)
+}
+
+func (p *Logger) withContext(ctx context.Context) *Logger {
+	c := p.clone()
+	c.ctx = ctx
+	if kvs := iface.ContextArgs(ctx); len(kvs) > 0 {
+		c.handler = c.handler.WithAttrs(argsToAttrs(kvs))
+	}
+	return c
+}
+
+func (p *Logger) Enabled(level iface.Level) bool {
+	return p.handler.Enabled(p.ctx, SlogLevel(level))
+}
+
+func (p *Logger) Debug(args ...interface{}) {
+	p.Print(1, iface.DEBUG, args...)
+}
+
+func (p *Logger) Debugf(format string, args ...interface{}) {
+	p.Printf(1, iface.DEBUG, format, args...)
+}
+
+func (p *Logger) Debugw(message string, kvs ...interface{}) {
+	p.Printw(1, iface.DEBUG, message, kvs...)
+}
+
+func (p *Logger) Info(args ...interface{}) {
+	p.Print(1, iface.INFO, args...)
+}
+
+func (p *Logger) Infof(format string, args ...interface{}) {
+	p.Printf(1, iface.INFO, format, args...)
+}
+
+func (p *Logger) Infow(message string, kvs ...interface{}) {
+	p.Printw(1, iface.INFO, message, kvs...)
+}
+
+func (p *Logger) Warn(args ...interface{}) {
+	p.Print(1, iface.WARN, args...)
+}
+
+func (p *Logger) Warnf(format string, args ...interface{}) {
+	p.Printf(1, iface.WARN, format, args...)
+}
+
+func (p *Logger) Warnw(message string, kvs ...interface{}) {
+	p.Printw(1, iface.WARN, message, kvs...)
+}
+
+func (p *Logger) Error(args ...interface{}) {
+	p.Print(1, iface.ERROR, args...)
+}
+
+func (p *Logger) Errorf(format string, args ...interface{}) {
+	p.Printf(1, iface.ERROR, format, args...)
+}
+
+func (p *Logger) Errorw(message string, kvs ...interface{}) {
+	p.Printw(1, iface.ERROR, message, kvs...)
+}
+
+func (p *Logger) Panic(args ...interface{}) {
+	p.Print(1, iface.PANIC, args...)
+}
+
+func (p *Logger) Panicf(format string, args ...interface{}) {
+	p.Printf(1, iface.PANIC, format, args...)
+}
+
+func (p *Logger) Panicw(message string, kvs ...interface{}) {
+	p.Printw(1, iface.PANIC, message, kvs...)
+}
+
+func (p *Logger) Fatal(args ...interface{}) {
+	p.Print(1, iface.FATAL, args...)
+}
+
+func (p *Logger) Fatalf(format string, args ...interface{}) {
+	p.Printf(1, iface.FATAL, format, args...)
+}
+
+func (p *Logger) Fatalw(message string, kvs ...interface{}) {
+	p.Printw(1, iface.FATAL, message, kvs...)
+}
+
+func (p *Logger) DebugContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.DEBUG, message, kvs...)
+}
+
+func (p *Logger) InfoContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.INFO, message, kvs...)
+}
+
+func (p *Logger) WarnContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.WARN, message, kvs...)
+}
+
+func (p *Logger) ErrorContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.ERROR, message, kvs...)
+}
+
+func (p *Logger) PanicContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.PANIC, message, kvs...)
+}
+
+func (p *Logger) FatalContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.FATAL, message, kvs...)
+}
+
+func (p *Logger) Print(depth int, level iface.Level, args ...interface{}) {
+	p.log(depth, level, "", args, nil)
+}
+
+func (p *Logger) Printf(depth int, level iface.Level, format string, args ...interface{}) {
+	p.log(depth, level, format, args, nil)
+}
+
+func (p *Logger) Printw(depth int, level iface.Level, message string, kvs ...interface{}) {
+	p.log(depth, level, message, nil, kvs)
+}
+
+func (p *Logger) Printc(ctx context.Context, depth int, level iface.Level, message string, kvs ...interface{}) {
+	// 日志级别未开启时, 不处理 ctx
+	if level < iface.PANIC && !p.handler.Enabled(ctx, SlogLevel(level)) {
+		return
+	}
+	p.withContext(ctx).log(depth, level, message, nil, kvs)
+}
+
+func (p *Logger) log(depth int, level iface.Level, template string, args []interface{}, kvs []interface{}) {
+	lvl := SlogLevel(level)
+	enabled := p.handler.Enabled(p.ctx, lvl)
+	if !enabled && level < iface.PANIC {
+		return
+	}
+
+	msg := template
+	if msg == "" && len(args) > 0 {
+		msg = fmt.Sprint(args...)
+	} else if msg != "" && len(args) > 0 {
+		msg = fmt.Sprintf(template, args...)
+	}
+
+	if enabled {
+		// 跳过 runtime.Callers, log 及 Print 系列方法
+		var pcs [1]uintptr
+		runtime.Callers(depth+3, pcs[:])
+		r := slog.NewRecord(time.Now(), lvl, msg, pcs[0])
+		if p.name != "" {
+			r.AddAttrs(slog.String(nameKey, p.name))
+		}
+		r.Add(slogArgs(kvs)...)
+		p.handler.Handle(p.ctx, r)
+	}
+
+	switch {
+	case level == iface.PANIC:
+		panic(msg)
+	case level > iface.PANIC:
+		for _, f := range p.fatal {
+			f()
+		}
+		p.exit(1)
+	}
+}
+
+func argsToAttrs(args []interface{}) []slog.Attr {
+	var r slog.Record
+	r.Add(slogArgs(args)...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+// slogValuer 将 iface.Valuer 转换为 slog.LogValuer, 由 slog 在输出时计算
+type slogValuer struct {
+	v iface.Valuer
+}
+
+func (p slogValuer) LogValue() slog.Value {
+	return slog.AnyValue(iface.Resolve(p.v))
+}
+
+// slogArgs 将参数中的 iface.Valuer 转换为 slog.LogValuer, iface.Field 转换为 slog.Attr, 没有时返回 args
+func slogArgs(args []interface{}) []interface{} {
+	var out []interface{}
+	for i, arg := range args {
+		var v interface{}
+		switch x := arg.(type) {
+		case iface.Valuer:
+			v = slogValuer{v: x}
+		case iface.Field:
+			v = slogAttr(x)
+		default:
+			if out != nil {
+				out = append(out, arg)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]interface{}, 0, len(args))
+			out = append(out, args[:i]...)
+		}
+		out = append(out, v)
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
+
+func slogAttr(f iface.Field) slog.Attr {
+	if f.Type == iface.SkipType {
+		return slog.Attr{}
+	}
+	if v, ok := f.Interface.(iface.Valuer); ok {
+		return slog.Any(f.Key, slogValuer{v: v})
+	}
+	return slog.Any(f.Key, f.Value())
+}
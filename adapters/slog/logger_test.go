@@ -0,0 +1,125 @@
+//go:build go1.21
+// +build go1.21
+
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+func newTestLogger(level slog.Level, opts ...Option) (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	h := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		AddSource: true,
+		Level:     level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey:
+				return slog.Attr{}
+			case slog.SourceKey:
+				src := a.Value.Any().(*slog.Source)
+				return slog.Bool("source", strings.HasSuffix(src.File, "logger_test.go"))
+			}
+			return a
+		},
+	})
+	return NewLogger(h, opts...), &buf
+}
+
+func TestLoggerLog(t *testing.T) {
+	logger, buf := newTestLogger(slog.LevelInfo)
+
+	logger.Debug("debug")
+	logger.Info("info ", 1)
+	logger.Warnf("warn %d", 2)
+	logger.Errorw("error", "k1", "v1")
+	logger.Print(0, iface.INFO, "print")
+	logger.Named("a").Named("b").WithArgs("k1", "v1").Infow("named", "k2", 2)
+	assert.Panics(t, func() { logger.Panicw("panic", "k1", "v1") }, "expected panic")
+
+	want := `level=INFO source=true msg="info 1"
+level=WARN source=true msg="warn 2"
+level=ERROR source=true msg=error k1=v1
+level=INFO source=true msg=print
+level=INFO source=true msg=named k1=v1 logger=a.b k2=2
+level=ERROR+4 source=true msg=panic k1=v1
+`
+	assert.Equal(t, want, buf.String(), "unexpected output")
+}
+
+func TestLoggerContext(t *testing.T) {
+	logger, buf := newTestLogger(slog.LevelInfo)
+
+	ctx := iface.ContextWithArgs(context.Background(), "request", "r1")
+	logger.DebugContext(ctx, "debug")
+	logger.InfoContext(ctx, "info", "k1", "v1")
+	logger.WithContext(ctx).Info("with context")
+	logger.Printc(ctx, 0, iface.WARN, "printc")
+
+	want := `level=INFO source=true msg=info request=r1 k1=v1
+level=INFO source=true msg="with context" request=r1
+level=WARN source=true msg=printc request=r1
+`
+	assert.Equal(t, want, buf.String(), "unexpected output")
+}
+
+type tLazyValuer struct{}
+
+func (tLazyValuer) LogValue() interface{} {
+	return "resolved"
+}
+
+func TestLoggerValuer(t *testing.T) {
+	logger, buf := newTestLogger(slog.LevelInfo)
+
+	logger.WithArgs("k1", tLazyValuer{}).Infow("valuer", "k2", tLazyValuer{}, "k3", 3)
+	logger.Infow("field", iface.Field{Key: "k1", Type: iface.Int64Type, Integer: 1}, "k2", 2,
+		iface.Field{Type: iface.SkipType}, iface.Field{Key: "k3", Type: iface.AnyType, Interface: tLazyValuer{}})
+
+	want := `level=INFO source=true msg=valuer k1=resolved k2=resolved k3=3
+level=INFO source=true msg=field k1=1 k2=2 k3=resolved
+`
+	assert.Equal(t, want, buf.String(), "unexpected output")
+}
+
+func TestLoggerFatal(t *testing.T) {
+	var calls []string
+	logger, buf := newTestLogger(slog.LevelInfo,
+		SetExitFunc(func(code int) { calls = append(calls, "exit") }),
+		OnFatal(func() { calls = append(calls, "fatal") }),
+	)
+
+	logger.Named("child").Fatalw("fatal", "k", 1)
+	assert.Equal(t, []string{"fatal", "exit"}, calls, "unexpected calls")
+	assert.Contains(t, buf.String(), "msg=fatal", "unexpected output")
+}
+
+func TestLoggerEnabled(t *testing.T) {
+	logger, _ := newTestLogger(slog.LevelWarn)
+	assert.False(t, logger.Enabled(iface.INFO))
+	assert.True(t, logger.Enabled(iface.WARN))
+	assert.True(t, logger.Enabled(iface.FATAL))
+}
+
+func TestLevel(t *testing.T) {
+	levels := []iface.Level{iface.DEBUG, iface.INFO, iface.WARN, iface.ERROR}
+	for _, l := range levels {
+		if got, want := LogLevel(SlogLevel(l)), l; got != want {
+			t.Errorf("level %v: got %v, want %v", l, got, want)
+		}
+	}
+	if got, want := SlogLevel(iface.PANIC), LevelPanic; got != want {
+		t.Errorf("panic: got %v, want %v", got, want)
+	}
+	if got, want := LogLevel(LevelFatal), iface.ERROR; got != want {
+		t.Errorf("fatal: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+// Package writer 提供将写入的数据按行输出到 tlog 日志对象的 io.Writer,
+// 可用于 exec.Cmd 的 Stdout/Stderr 等只接受 io.Writer 的场景
+package writer
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+type Option func(*Writer)
+
+// SetDepth 设置跳过的调用层数, 默认为 0, 即日志的调用位置为 Write 的调用方
+func SetDepth(depth int) Option {
+	return func(w *Writer) {
+		w.depth = depth
+	}
+}
+
+// Writer 将写入的数据按行以指定级别输出, 不完整的行缓存到下次写入或 Sync/Close 时输出
+type Writer struct {
+	logger iface.Logger
+	level  iface.Level
+	depth  int
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// New 构造 Writer
+func New(logger iface.Logger, level iface.Level, opts ...Option) *Writer {
+	w := &Writer{logger: logger, level: level}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+func (w *Writer) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := len(b)
+	for {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			w.buf.Write(b)
+			break
+		}
+		w.print(w.line(b[:i]))
+		b = b[i+1:]
+	}
+	return n, nil
+}
+
+// Sync 输出缓存的不完整行
+func (w *Writer) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.buf.Len() > 0 {
+		w.print(w.line(nil))
+	}
+	return nil
+}
+
+// Close 输出缓存的不完整行
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.buf.Len() > 0 {
+		w.print(w.line(nil))
+	}
+	return nil
+}
+
+// line 返回缓存的数据与 b 组成的行
+func (w *Writer) line(b []byte) string {
+	if w.buf.Len() <= 0 {
+		return string(b)
+	}
+	w.buf.Write(b)
+	s := w.buf.String()
+	w.buf.Reset()
+	return s
+}
+
+func (w *Writer) print(line string) {
+	// 跳过 print 及 Write/Sync/Close
+	w.logger.Printw(w.depth+2, w.level, line)
+}
@@ -0,0 +1,46 @@
+package writer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zlogger"
+)
+
+func TestWriter(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	w := New(zlogger.New("", core, nil, zap.AddCaller()), iface.WARN)
+
+	fmt.Fprint(w, "line1\nline2\npart")
+	fmt.Fprint(w, "ial\n\nline")
+	w.Sync()
+	w.Write([]byte("tail"))
+	w.Close()
+
+	var messages []string
+	for _, e := range logs.AllUntimed() {
+		messages = append(messages, e.Message)
+		assert.Equal(t, zapcore.WarnLevel, e.Level, "unexpected level")
+	}
+	assert.Equal(t, []string{"line1", "line2", "partial", "", "line", "tail"}, messages, "unexpected messages")
+}
+
+func TestWriterCaller(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	w := New(zlogger.New("", core, nil, zap.AddCaller()), iface.INFO, SetDepth(1))
+
+	fmt.Fprintln(w, "hello")
+	w.Write([]byte("world"))
+	w.Close()
+
+	entries := logs.AllUntimed()
+	assert.Equal(t, 2, len(entries), "unexpected number of logs written out")
+	assert.Regexp(t, `writer_test.go`, entries[0].Caller.String(), "unexpected caller")
+	assert.Regexp(t, `testing/testing.go`, entries[1].Caller.String(), "unexpected caller")
+}
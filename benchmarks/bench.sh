@@ -0,0 +1,20 @@
+#!/bin/bash
+# 用法: ./bench.sh [版本], 版本默认为 git describe 的输出;
+# 结果写入 results/<版本>.txt, 安装了 benchstat 时与上一次的结果对比
+
+set -e
+
+cd "$(dirname "$0")"
+
+version=${1:-$(git describe --tags --always --dirty)}
+count=${COUNT:-5}
+output=results/${version}.txt
+
+mkdir -p results
+last=$(ls -t results/*.txt 2>/dev/null | grep -v "^${output}$" | head -n 1 || true)
+
+go test -run NONE -bench "${BENCH:-.}" -benchmem -count "${count}" . | tee "${output}"
+
+if [ -n "${last}" ] && command -v benchstat >/dev/null 2>&1; then
+	benchstat "${last}" "${output}"
+fi
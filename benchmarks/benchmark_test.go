@@ -0,0 +1,255 @@
+package benchmarks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog"
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog"
+	"github.com/ironzhang/tlog/zaplog/zsink"
+	"github.com/ironzhang/tlog/zaplog/zsink/rollfile"
+)
+
+var encodings = []string{"json", "console"}
+
+type discardSink struct{}
+
+func (discardSink) Write(p []byte) (int, error) { return len(p), nil }
+func (discardSink) Sync() error                 { return nil }
+func (discardSink) Close() error                { return nil }
+
+func init() {
+	err := zsink.RegisterSink("discard", func(u *url.URL) (zap.Sink, error) {
+		return discardSink{}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func newTlogConfig(encoding string, level iface.Level, url string) zaplog.Config {
+	encoder := zaplog.NewJSONEncoderConfig()
+	if encoding == "console" {
+		encoder = zaplog.NewConsoleEncoderConfig()
+	}
+	return zaplog.Config{
+		Level: level,
+		Cores: []zaplog.CoreConfig{
+			{
+				Name:     "Bench",
+				Encoding: encoding,
+				Encoder:  encoder,
+				MinLevel: iface.DEBUG,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{url},
+			},
+		},
+		Loggers: []zaplog.LoggerConfig{
+			{
+				Cores: []string{"Bench"},
+			},
+		},
+	}
+}
+
+func newTlogLogger(b *testing.B, encoding string, level iface.Level) *zaplog.Logger {
+	logger, err := zaplog.New(newTlogConfig(encoding, level, "discard://"))
+	if err != nil {
+		b.Fatalf("new tlog logger: %v", err)
+	}
+	return logger
+}
+
+// newZapLogger 返回与 newTlogLogger 编码配置相同的 zap logger
+func newZapLogger(encoding string, level zapcore.Level) *zap.Logger {
+	cfg := zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		LevelKey:       "level",
+		TimeKey:        "ts",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.EpochTimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+	}
+	enc := zapcore.NewJSONEncoder(cfg)
+	if encoding == "console" {
+		cfg.MessageKey, cfg.LevelKey, cfg.TimeKey = "M", "L", "T"
+		cfg.NameKey, cfg.CallerKey, cfg.StacktraceKey = "N", "C", "S"
+		cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+		cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+		enc = zapcore.NewConsoleEncoder(cfg)
+	}
+	core := zapcore.NewCore(enc, zapcore.AddSync(ioutil.Discard), level)
+	return zap.New(core, zap.AddCaller())
+}
+
+func runParallel(b *testing.B, f func()) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			f()
+		}
+	})
+}
+
+func BenchmarkInfow(b *testing.B) {
+	for _, encoding := range encodings {
+		b.Run("tlog/"+encoding, func(b *testing.B) {
+			logger := newTlogLogger(b, encoding, iface.DEBUG)
+			defer logger.Close()
+			runParallel(b, func() {
+				logger.Infow("benchmark", "k1", "v1", "k2", 2, "k3", true)
+			})
+		})
+		b.Run("zap.Sugar/"+encoding, func(b *testing.B) {
+			logger := newZapLogger(encoding, zapcore.DebugLevel).Sugar()
+			runParallel(b, func() {
+				logger.Infow("benchmark", "k1", "v1", "k2", 2, "k3", true)
+			})
+		})
+		b.Run("zap/"+encoding, func(b *testing.B) {
+			logger := newZapLogger(encoding, zapcore.DebugLevel)
+			runParallel(b, func() {
+				logger.Info("benchmark", zap.String("k1", "v1"), zap.Int("k2", 2), zap.Bool("k3", true))
+			})
+		})
+	}
+}
+
+func BenchmarkTopLevelInfow(b *testing.B) {
+	logger := newTlogLogger(b, "json", iface.DEBUG)
+	defer logger.Close()
+	old := tlog.SetLogger(logger)
+	defer tlog.SetLogger(old)
+
+	b.Run("tlog.Infow", func(b *testing.B) {
+		runParallel(b, func() {
+			tlog.Infow("benchmark", "k1", "v1", "k2", 2, "k3", true)
+		})
+	})
+	b.Run("Logger.Infow", func(b *testing.B) {
+		runParallel(b, func() {
+			logger.Infow("benchmark", "k1", "v1", "k2", 2, "k3", true)
+		})
+	})
+}
+
+func BenchmarkNamedWithArgs(b *testing.B) {
+	b.Run("tlog/Chain", func(b *testing.B) {
+		logger := newTlogLogger(b, "json", iface.DEBUG)
+		defer logger.Close()
+		runParallel(b, func() {
+			logger.Named("access").WithArgs("request", "r1", "user", 1).Infow("benchmark", "k1", "v1")
+		})
+	})
+	b.Run("tlog/Bound", func(b *testing.B) {
+		logger := newTlogLogger(b, "json", iface.DEBUG)
+		defer logger.Close()
+		bound := logger.Named("access").WithArgs("request", "r1", "user", 1)
+		runParallel(b, func() {
+			bound.Infow("benchmark", "k1", "v1")
+		})
+	})
+	b.Run("zap.Sugar/Chain", func(b *testing.B) {
+		logger := newZapLogger("json", zapcore.DebugLevel).Sugar()
+		runParallel(b, func() {
+			logger.Named("access").With("request", "r1", "user", 1).Infow("benchmark", "k1", "v1")
+		})
+	})
+	b.Run("zap.Sugar/Bound", func(b *testing.B) {
+		bound := newZapLogger("json", zapcore.DebugLevel).Sugar().Named("access").With("request", "r1", "user", 1)
+		runParallel(b, func() {
+			bound.Infow("benchmark", "k1", "v1")
+		})
+	})
+}
+
+func BenchmarkDisabled(b *testing.B) {
+	b.Run("tlog", func(b *testing.B) {
+		logger := newTlogLogger(b, "json", iface.WARN)
+		defer logger.Close()
+		runParallel(b, func() {
+			logger.Infow("benchmark", "k1", "v1", "k2", 2, "k3", true)
+		})
+	})
+	b.Run("tlog.Infow", func(b *testing.B) {
+		logger := newTlogLogger(b, "json", iface.WARN)
+		defer logger.Close()
+		old := tlog.SetLogger(logger)
+		defer tlog.SetLogger(old)
+		runParallel(b, func() {
+			tlog.Infow("benchmark", "k1", "v1", "k2", 2, "k3", true)
+		})
+	})
+	b.Run("zap.Sugar", func(b *testing.B) {
+		logger := newZapLogger("json", zapcore.WarnLevel).Sugar()
+		runParallel(b, func() {
+			logger.Infow("benchmark", "k1", "v1", "k2", 2, "k3", true)
+		})
+	})
+	b.Run("zap", func(b *testing.B) {
+		logger := newZapLogger("json", zapcore.WarnLevel)
+		runParallel(b, func() {
+			logger.Info("benchmark", zap.String("k1", "v1"), zap.Int("k2", 2), zap.Bool("k3", true))
+		})
+	})
+}
+
+func tempDir(b *testing.B) string {
+	dir, err := ioutil.TempDir("", "tlog-benchmarks")
+	if err != nil {
+		b.Fatalf("temp dir: %v", err)
+	}
+	return dir
+}
+
+// BenchmarkRollFile 测试不同并发数下 rollfile 的写入吞吐
+func BenchmarkRollFile(b *testing.B) {
+	line := []byte(`{"level":"info","ts":1577808000.000001,"caller":"benchmarks/benchmark_test.go:1","msg":"benchmark","k1":"v1"}` + "\n")
+	for _, p := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("File/Parallelism%d", p), func(b *testing.B) {
+			dir := tempDir(b)
+			defer os.RemoveAll(dir)
+			f, err := rollfile.Open(filepath.Join(dir, "bench.log"), rollfile.SetMaxSize(64*1024*1024), rollfile.SetMaxSeq(2))
+			if err != nil {
+				b.Fatalf("open: %v", err)
+			}
+			defer f.Close()
+
+			b.SetBytes(int64(len(line)))
+			b.SetParallelism(p)
+			runParallel(b, func() {
+				f.Write(line)
+			})
+		})
+		b.Run(fmt.Sprintf("tlog/Parallelism%d", p), func(b *testing.B) {
+			dir := tempDir(b)
+			defer os.RemoveAll(dir)
+			url := "rfile://localhost" + filepath.ToSlash(filepath.Join(dir, "bench.log")) + "?maxSize=64M&maxSeq=2"
+			logger, err := zaplog.New(newTlogConfig("json", iface.DEBUG, url))
+			if err != nil {
+				b.Fatalf("new tlog logger: %v", err)
+			}
+			defer logger.Close()
+
+			b.SetParallelism(p)
+			runParallel(b, func() {
+				logger.Infow("benchmark", "k1", "v1")
+			})
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package benchmarks 对比 tlog 与 zap, log/slog 的性能, 只包含基准测试;
+// 运行 bench.sh 记录结果, 以便发现版本之间的性能回退.
+package benchmarks
@@ -0,0 +1,70 @@
+//go:build go1.21
+// +build go1.21
+
+package benchmarks
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	tslog "github.com/ironzhang/tlog/adapters/slog"
+	"github.com/ironzhang/tlog/iface"
+)
+
+func newSlogLogger(encoding string, level slog.Level) *slog.Logger {
+	opts := &slog.HandlerOptions{AddSource: true, Level: level}
+	if encoding == "console" {
+		return slog.New(slog.NewTextHandler(io.Discard, opts))
+	}
+	return slog.New(slog.NewJSONHandler(io.Discard, opts))
+}
+
+func BenchmarkSlogInfo(b *testing.B) {
+	for _, encoding := range encodings {
+		b.Run("slog/"+encoding, func(b *testing.B) {
+			logger := newSlogLogger(encoding, slog.LevelDebug)
+			runParallel(b, func() {
+				logger.Info("benchmark", "k1", "v1", "k2", 2, "k3", true)
+			})
+		})
+		b.Run("slog.LogAttrs/"+encoding, func(b *testing.B) {
+			logger := newSlogLogger(encoding, slog.LevelDebug)
+			ctx := context.Background()
+			runParallel(b, func() {
+				logger.LogAttrs(ctx, slog.LevelInfo, "benchmark", slog.String("k1", "v1"), slog.Int("k2", 2), slog.Bool("k3", true))
+			})
+		})
+		b.Run("slog+tlog.Handler/"+encoding, func(b *testing.B) {
+			logger := newTlogLogger(b, encoding, iface.DEBUG)
+			defer logger.Close()
+			slogger := slog.New(tslog.NewHandler(logger))
+			runParallel(b, func() {
+				slogger.Info("benchmark", "k1", "v1", "k2", 2, "k3", true)
+			})
+		})
+	}
+}
+
+func BenchmarkSlogWith(b *testing.B) {
+	b.Run("slog/Chain", func(b *testing.B) {
+		logger := newSlogLogger("json", slog.LevelDebug)
+		runParallel(b, func() {
+			logger.With("logger", "access").With("request", "r1", "user", 1).Info("benchmark", "k1", "v1")
+		})
+	})
+	b.Run("slog/Bound", func(b *testing.B) {
+		bound := newSlogLogger("json", slog.LevelDebug).With("logger", "access").With("request", "r1", "user", 1)
+		runParallel(b, func() {
+			bound.Info("benchmark", "k1", "v1")
+		})
+	})
+}
+
+func BenchmarkSlogDisabled(b *testing.B) {
+	logger := newSlogLogger("json", slog.LevelWarn)
+	runParallel(b, func() {
+		logger.Info("benchmark", "k1", "v1", "k2", 2, "k3", true)
+	})
+}
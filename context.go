@@ -0,0 +1,29 @@
+package tlog
+
+import (
+	"context"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+type loggerKey struct{}
+
+// NewContext 返回携带 logger 的 context
+func NewContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext 返回 ctx 携带的 logger, 没有时返回 GetLogger()
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(Logger); ok {
+			return logger
+		}
+	}
+	return GetLogger()
+}
+
+// ContextWithArgs 返回携带日志字段的 context, Logger.WithContext 会自动输出这些字段
+func ContextWithArgs(ctx context.Context, kvs ...interface{}) context.Context {
+	return iface.ContextWithArgs(ctx, kvs...)
+}
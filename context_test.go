@@ -0,0 +1,31 @@
+package tlog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ironzhang/tlog"
+	"github.com/ironzhang/tlog/iface"
+)
+
+func TestContextLogger(t *testing.T) {
+	if got, want := tlog.FromContext(context.Background()), tlog.GetLogger(); got != want {
+		t.Errorf("from context: got %v, want %v", got, want)
+	}
+
+	logger := tlog.Named("request").WithArgs("request", "r1")
+	ctx := tlog.NewContext(context.Background(), logger)
+	if got, want := tlog.FromContext(ctx), logger; got != want {
+		t.Errorf("from context: got %v, want %v", got, want)
+	}
+	tlog.FromContext(ctx).Info("from context")
+}
+
+func TestContextWithArgs(t *testing.T) {
+	ctx := tlog.ContextWithArgs(context.Background(), "request", "r1")
+	ctx = tlog.ContextWithArgs(ctx, "user", "u1")
+	if got, want := len(iface.ContextArgs(ctx)), 4; got != want {
+		t.Errorf("args: got %d, want %d", got, want)
+	}
+	tlog.WithContext(ctx).Info("with context args")
+}
@@ -30,4 +30,8 @@ func main() {
 
 	ctx := context.WithValue(context.Background(), "trace_id", "123456")
 	tlog.WithContext(ctx).Info("hello, world") // 输出日志
+
+	ctx = tlog.ContextWithArgs(ctx, "user", "u1")                        // 在 context 中添加日志字段
+	ctx = tlog.NewContext(ctx, tlog.Named("handler"))                    // 在 context 中保存日志对象
+	tlog.FromContext(ctx).WithContext(ctx).Info("hello, context logger") // 输出日志
 }
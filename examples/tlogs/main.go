@@ -1,42 +1,20 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
-	"path"
-
-	"gopkg.in/yaml.v2"
 
 	"github.com/ironzhang/tlog"
 	"github.com/ironzhang/tlog/zaplog"
 )
 
-func LoadConfig(file string) (conf zaplog.Config, err error) {
-	data, err := ioutil.ReadFile(file)
-	if err != nil {
-		return conf, err
-	}
-
-	unmarshal := json.Unmarshal
-	if path.Ext(file) == ".yaml" {
-		unmarshal = yaml.Unmarshal
-	}
-
-	if err = unmarshal(data, &conf); err != nil {
-		return conf, err
-	}
-	return conf, nil
-}
-
 func main() {
 	file := "../configs/development.json"
 	if len(os.Args) >= 2 {
 		file = os.Args[1]
 	}
 
-	cfg, err := LoadConfig(file)
+	cfg, err := zaplog.LoadConfig(file)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "load config: %v\n", err)
 		return
@@ -46,9 +24,8 @@ func main() {
 		fmt.Fprintf(os.Stderr, "new logger: %v\n", err)
 		return
 	}
-	defer logger.Close()
-
-	tlog.SetLogger(logger)
+	tlog.SetLogger(logger, tlog.CloseOld(0))
+	defer tlog.Close()
 
 	run()
 }
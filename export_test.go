@@ -0,0 +1,6 @@
+package tlog
+
+// ResetLogger 恢复为未调用过 SetLogger 的状态
+func ResetLogger() {
+	logging.Store(&loggerHolder{})
+}
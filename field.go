@@ -0,0 +1,91 @@
+package tlog
+
+import (
+	"math"
+	"time"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zbase"
+)
+
+// Field 强类型的日志字段, 可以与键值对混合使用, 如:
+//
+//	tlog.Infow("request", tlog.String("method", "GET"), tlog.Int("status", 200), "cost", cost)
+type Field = iface.Field
+
+// ObjectMarshaler 由可以自行编码为日志对象的类型实现
+type ObjectMarshaler = iface.ObjectMarshaler
+
+// ObjectEncoder 对象编码接口
+type ObjectEncoder = iface.ObjectEncoder
+
+func String(key string, value string) Field {
+	return Field{Key: key, Type: iface.StringType, String: value}
+}
+
+func Int(key string, value int) Field {
+	return Int64(key, int64(value))
+}
+
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: iface.Int64Type, Integer: value}
+}
+
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Type: iface.Uint64Type, Integer: int64(value)}
+}
+
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: iface.Float64Type, Integer: int64(math.Float64bits(value))}
+}
+
+func Bool(key string, value bool) Field {
+	var n int64
+	if value {
+		n = 1
+	}
+	return Field{Key: key, Type: iface.BoolType, Integer: n}
+}
+
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: iface.DurationType, Integer: int64(value)}
+}
+
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Type: iface.TimeType, Integer: value.UnixNano(), Interface: value.Location()}
+}
+
+// Err 返回字段名为 error 的错误字段, err 为 nil 时不输出
+func Err(err error) Field {
+	return NamedErr("error", err)
+}
+
+// NamedErr 返回错误字段, err 为 nil 时不输出
+func NamedErr(key string, err error) Field {
+	if err == nil {
+		return Field{Type: iface.SkipType}
+	}
+	return Field{Key: key, Type: iface.ErrorType, Interface: err}
+}
+
+// Errw 返回字段名为 error 的错误字段, 输出错误的原因链及调用栈;
+// err 未带有调用栈时记录调用 Errw 处的调用栈, err 为 nil 时不输出
+func Errw(err error) Field {
+	if err == nil {
+		return Field{Type: iface.SkipType}
+	}
+	if _, ok := zbase.ErrorStack(err); !ok {
+		err = zbase.WithStack(err, 1)
+	}
+	return Field{Key: "error", Type: iface.ErrorType, Interface: err}
+}
+
+// Object 返回由 ObjectMarshaler 自行编码的对象字段
+func Object(key string, value ObjectMarshaler) Field {
+	return Field{Key: key, Type: iface.ObjectType, Interface: value}
+}
+
+// Any 返回任意类型的字段, 由日志实现选择编码方式
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Type: iface.AnyType, Interface: value}
+}
@@ -0,0 +1,81 @@
+package tlog_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ironzhang/tlog"
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zbase"
+)
+
+type tObject struct{}
+
+func (tObject) MarshalLogObject(enc tlog.ObjectEncoder) error {
+	enc.AddString("k", "v")
+	return nil
+}
+
+func TestFieldValue(t *testing.T) {
+	now := time.Now()
+	err := errors.New("EOF")
+	tests := []struct {
+		f    tlog.Field
+		want interface{}
+	}{
+		{f: tlog.String("k", "v"), want: "v"},
+		{f: tlog.Int("k", -1), want: int64(-1)},
+		{f: tlog.Int64("k", math.MinInt64), want: int64(math.MinInt64)},
+		{f: tlog.Uint64("k", math.MaxUint64), want: uint64(math.MaxUint64)},
+		{f: tlog.Float64("k", -1.5), want: -1.5},
+		{f: tlog.Bool("k", true), want: true},
+		{f: tlog.Bool("k", false), want: false},
+		{f: tlog.Duration("k", time.Second), want: time.Second},
+		{f: tlog.Err(err), want: err},
+		{f: tlog.Err(nil), want: nil},
+		{f: tlog.Object("k", tObject{}), want: tObject{}},
+		{f: tlog.Any("k", []int{1}), want: []int{1}},
+	}
+	for i, tt := range tests {
+		assert.Equal(t, tt.want, tt.f.Value(), "%d: unexpected value", i)
+	}
+
+	got := tlog.Time("k", now).Value().(time.Time)
+	assert.True(t, got.Equal(now), "unexpected time")
+	assert.Equal(t, now.Location(), got.Location(), "unexpected location")
+}
+
+func TestFieldLog(t *testing.T) {
+	logger, logs := newObservedLogger()
+	logger.Infow("field", tlog.String("k1", "v1"), "k2", 2, tlog.Err(nil), tlog.Duration("d", time.Second))
+
+	entries := logs.AllUntimed()
+	if got, want := len(entries), 1; got != want {
+		t.Fatalf("entries: got %d, want %d", got, want)
+	}
+	want := map[string]interface{}{"k1": "v1", "k2": int64(2), "d": time.Second}
+	assert.Equal(t, want, entries[0].ContextMap(), "unexpected context")
+}
+
+func TestErrw(t *testing.T) {
+	assert.Equal(t, iface.SkipType, tlog.Errw(nil).Type, "unexpected nil error field type")
+
+	err := errors.New("EOF")
+	f := tlog.Errw(err)
+	assert.Equal(t, "error", f.Key, "unexpected key")
+	assert.Equal(t, iface.ErrorType, f.Type, "unexpected type")
+	werr := f.Value().(error)
+	assert.True(t, errors.Is(werr, err), "unexpected error")
+	assert.Equal(t, "EOF", werr.Error(), "unexpected error message")
+
+	stack, ok := zbase.ErrorStack(werr)
+	assert.True(t, ok, "no stack captured")
+	assert.Contains(t, strings.SplitN(stack, "\n", 2)[0], "TestErrw", "unexpected stack top frame")
+
+	assert.Equal(t, werr, tlog.Errw(werr).Value(), "stack is captured again")
+}
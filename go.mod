@@ -3,7 +3,10 @@ module github.com/ironzhang/tlog
 go 1.13
 
 require (
-	github.com/stretchr/testify v1.4.0
+	github.com/go-logr/logr v1.2.4
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
 	go.uber.org/multierr v1.3.0
 	go.uber.org/zap v1.13.0
 	gopkg.in/yaml.v2 v2.2.2
@@ -0,0 +1,27 @@
+package iface
+
+import "context"
+
+type argsKey struct{}
+
+// ContextWithArgs 返回携带日志字段的 context, kvs 追加在 ctx 已携带的字段之后;
+// Logger.WithContext 会自动输出这些字段
+func ContextWithArgs(ctx context.Context, kvs ...interface{}) context.Context {
+	if len(kvs) <= 0 {
+		return ctx
+	}
+	prev := ContextArgs(ctx)
+	args := make([]interface{}, 0, len(prev)+len(kvs))
+	args = append(args, prev...)
+	args = append(args, kvs...)
+	return context.WithValue(ctx, argsKey{}, args)
+}
+
+// ContextArgs 返回 ctx 携带的日志字段
+func ContextArgs(ctx context.Context) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+	args, _ := ctx.Value(argsKey{}).([]interface{})
+	return args
+}
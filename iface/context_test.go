@@ -0,0 +1,35 @@
+package iface
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestContextWithArgs(t *testing.T) {
+	ctx := context.Background()
+	if got := ContextArgs(ctx); got != nil {
+		t.Errorf("args: got %v, want nil", got)
+	}
+
+	ctx1 := ContextWithArgs(ctx, "k1", "v1")
+	ctx2 := ContextWithArgs(ctx1, "k2", 2)
+	ctx3 := ContextWithArgs(ctx1, "k3", 3)
+	if got := ContextWithArgs(ctx2); got != ctx2 {
+		t.Errorf("empty args: got a new context")
+	}
+
+	tests := []struct {
+		ctx  context.Context
+		args []interface{}
+	}{
+		{ctx: ctx1, args: []interface{}{"k1", "v1"}},
+		{ctx: ctx2, args: []interface{}{"k1", "v1", "k2", 2}},
+		{ctx: ctx3, args: []interface{}{"k1", "v1", "k3", 3}},
+	}
+	for i, tt := range tests {
+		if got, want := ContextArgs(tt.ctx), tt.args; !reflect.DeepEqual(got, want) {
+			t.Errorf("%d: args: got %v, want %v", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package iface
+
+import (
+	"math"
+	"time"
+)
+
+// FieldType 日志字段类型
+type FieldType uint8
+
+// 日志字段类型常量定义
+const (
+	UnknownType FieldType = iota
+	SkipType
+	StringType
+	Int64Type
+	Uint64Type
+	Float64Type
+	BoolType
+	DurationType
+	TimeType
+	ErrorType
+	ObjectType
+	AnyType
+)
+
+// Field 强类型的日志字段, 可以与键值对混合传给 Printw 等方法,
+// 基本类型的值保存在 Integer 及 String 中, 避免转换为 interface{}
+type Field struct {
+	Key       string
+	Type      FieldType
+	Integer   int64
+	String    string
+	Interface interface{}
+}
+
+// Value 返回字段的值
+func (f Field) Value() interface{} {
+	switch f.Type {
+	case StringType:
+		return f.String
+	case Int64Type:
+		return f.Integer
+	case Uint64Type:
+		return uint64(f.Integer)
+	case Float64Type:
+		return math.Float64frombits(uint64(f.Integer))
+	case BoolType:
+		return f.Integer == 1
+	case DurationType:
+		return time.Duration(f.Integer)
+	case TimeType:
+		if loc, ok := f.Interface.(*time.Location); ok {
+			return time.Unix(0, f.Integer).In(loc)
+		}
+		return time.Unix(0, f.Integer)
+	default:
+		return f.Interface
+	}
+}
+
+// ObjectEncoder 对象编码接口, zapcore.ObjectEncoder 实现了该接口
+type ObjectEncoder interface {
+	AddString(key, value string)
+	AddInt64(key string, value int64)
+	AddUint64(key string, value uint64)
+	AddFloat64(key string, value float64)
+	AddBool(key string, value bool)
+	AddDuration(key string, value time.Duration)
+	AddTime(key string, value time.Time)
+	AddReflected(key string, value interface{}) error
+}
+
+// ObjectMarshaler 由可以自行编码为日志对象的类型实现, 避免反射
+type ObjectMarshaler interface {
+	MarshalLogObject(enc ObjectEncoder) error
+}
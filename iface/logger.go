@@ -2,6 +2,7 @@ package iface
 
 import (
 	"context"
+	"time"
 )
 
 // Logger 日志接口
@@ -34,9 +35,30 @@ type Logger interface {
 	Fatalf(format string, args ...interface{})
 	Fatalw(message string, kvs ...interface{})
 
+	DebugContext(ctx context.Context, message string, kvs ...interface{})
+	InfoContext(ctx context.Context, message string, kvs ...interface{})
+	WarnContext(ctx context.Context, message string, kvs ...interface{})
+	ErrorContext(ctx context.Context, message string, kvs ...interface{})
+	PanicContext(ctx context.Context, message string, kvs ...interface{})
+	FatalContext(ctx context.Context, message string, kvs ...interface{})
+
 	Print(depth int, level Level, args ...interface{})
 	Printf(depth int, level Level, format string, args ...interface{})
 	Printw(depth int, level Level, message string, kvs ...interface{})
+
+	// Printc 与 WithContext(ctx).Printw 等价, 但只在日志级别开启时才处理 ctx
+	Printc(ctx context.Context, depth int, level Level, message string, kvs ...interface{})
+}
+
+// EntryPrinter 由可以指定日志时间及调用位置的日志对象实现, 用于适配其他日志库(如 log/slog);
+// t 为零值时使用当前时间, pc 为 0 时不输出调用位置
+type EntryPrinter interface {
+	PrintEntry(ctx context.Context, t time.Time, pc uintptr, level Level, message string, kvs ...interface{})
+}
+
+// LevelEnabler 判断日志级别是否开启接口
+type LevelEnabler interface {
+	Enabled(level Level) bool
 }
 
 // GetSetLevel 获取设置日志等级接口
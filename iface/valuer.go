@@ -0,0 +1,21 @@
+package iface
+
+// maxResolveDepth 限制 Valuer 嵌套的层数, 避免 LogValue 返回自身时死循环
+const maxResolveDepth = 16
+
+// Valuer 由需要延迟计算的日志字段值实现, 只在日志确实输出时才调用 LogValue, 计算结果按其类型编码
+type Valuer interface {
+	LogValue() interface{}
+}
+
+// Resolve 返回 v 的值, v 为 Valuer 时返回 LogValue 的结果
+func Resolve(v interface{}) interface{} {
+	for i := 0; i < maxResolveDepth; i++ {
+		valuer, ok := v.(Valuer)
+		if !ok {
+			return v
+		}
+		v = valuer.LogValue()
+	}
+	return v
+}
@@ -0,0 +1,35 @@
+package iface
+
+import "testing"
+
+type tValuer struct {
+	v interface{}
+}
+
+func (p tValuer) LogValue() interface{} {
+	return p.v
+}
+
+type tLoopValuer struct{}
+
+func (p tLoopValuer) LogValue() interface{} {
+	return p
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want interface{}
+	}{
+		{v: nil, want: nil},
+		{v: 1, want: 1},
+		{v: tValuer{v: "a"}, want: "a"},
+		{v: tValuer{v: tValuer{v: 2}}, want: 2},
+		{v: tLoopValuer{}, want: tLoopValuer{}},
+	}
+	for i, tt := range tests {
+		if got, want := Resolve(tt.v), tt.want; got != want {
+			t.Errorf("%d: resolve: got %v, want %v", i, got, want)
+		}
+	}
+}
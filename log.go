@@ -2,6 +2,13 @@ package tlog
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ironzhang/tlog/iface"
 	"github.com/ironzhang/tlog/zaplog"
@@ -48,117 +55,369 @@ func (p nopLogger) Print(depth int, level Level, args ...interface{})
 func (p nopLogger) Printf(depth int, level Level, format string, args ...interface{}) {}
 func (p nopLogger) Printw(depth int, level Level, message string, kvs ...interface{}) {}
 
-func init() {
-	SetLogger(zaplog.StdLogger())
+func (p nopLogger) DebugContext(ctx context.Context, message string, kvs ...interface{}) {}
+func (p nopLogger) InfoContext(ctx context.Context, message string, kvs ...interface{})  {}
+func (p nopLogger) WarnContext(ctx context.Context, message string, kvs ...interface{})  {}
+func (p nopLogger) ErrorContext(ctx context.Context, message string, kvs ...interface{}) {}
+func (p nopLogger) PanicContext(ctx context.Context, message string, kvs ...interface{}) {}
+func (p nopLogger) FatalContext(ctx context.Context, message string, kvs ...interface{}) {}
+func (p nopLogger) Printc(ctx context.Context, depth int, level Level, message string, kvs ...interface{}) {
 }
 
-var logging Logger
+// stdLogger 代表尚未创建的 zaplog.StdLogger(), 由未调用过 SetLogger 时的 SetLogger 返回,
+// 调用其方法时才创建 zaplog.StdLogger(), 再次设置为全局日志对象时恢复为默认的日志对象
+type stdLogger struct {
+}
+
+func (stdLogger) Named(name string) Logger               { return zaplog.StdLogger().Named(name) }
+func (stdLogger) WithArgs(args ...interface{}) Logger    { return zaplog.StdLogger().WithArgs(args...) }
+func (stdLogger) WithContext(ctx context.Context) Logger { return zaplog.StdLogger().WithContext(ctx) }
+func (stdLogger) Enabled(level Level) bool               { return zaplog.StdLogger().Enabled(level) }
+
+func (stdLogger) Debug(args ...interface{}) { zaplog.StdLogger().Print(1, DEBUG, args...) }
+func (stdLogger) Debugf(format string, args ...interface{}) {
+	zaplog.StdLogger().Printf(1, DEBUG, format, args...)
+}
+func (stdLogger) Debugw(message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printw(1, DEBUG, message, kvs...)
+}
+func (stdLogger) Info(args ...interface{}) { zaplog.StdLogger().Print(1, INFO, args...) }
+func (stdLogger) Infof(format string, args ...interface{}) {
+	zaplog.StdLogger().Printf(1, INFO, format, args...)
+}
+func (stdLogger) Infow(message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printw(1, INFO, message, kvs...)
+}
+func (stdLogger) Warn(args ...interface{}) { zaplog.StdLogger().Print(1, WARN, args...) }
+func (stdLogger) Warnf(format string, args ...interface{}) {
+	zaplog.StdLogger().Printf(1, WARN, format, args...)
+}
+func (stdLogger) Warnw(message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printw(1, WARN, message, kvs...)
+}
+func (stdLogger) Error(args ...interface{}) { zaplog.StdLogger().Print(1, ERROR, args...) }
+func (stdLogger) Errorf(format string, args ...interface{}) {
+	zaplog.StdLogger().Printf(1, ERROR, format, args...)
+}
+func (stdLogger) Errorw(message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printw(1, ERROR, message, kvs...)
+}
+func (stdLogger) Panic(args ...interface{}) { zaplog.StdLogger().Print(1, PANIC, args...) }
+func (stdLogger) Panicf(format string, args ...interface{}) {
+	zaplog.StdLogger().Printf(1, PANIC, format, args...)
+}
+func (stdLogger) Panicw(message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printw(1, PANIC, message, kvs...)
+}
+func (stdLogger) Fatal(args ...interface{}) { zaplog.StdLogger().Print(1, FATAL, args...) }
+func (stdLogger) Fatalf(format string, args ...interface{}) {
+	zaplog.StdLogger().Printf(1, FATAL, format, args...)
+}
+func (stdLogger) Fatalw(message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printw(1, FATAL, message, kvs...)
+}
+
+func (stdLogger) DebugContext(ctx context.Context, message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printc(ctx, 1, DEBUG, message, kvs...)
+}
+func (stdLogger) InfoContext(ctx context.Context, message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printc(ctx, 1, INFO, message, kvs...)
+}
+func (stdLogger) WarnContext(ctx context.Context, message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printc(ctx, 1, WARN, message, kvs...)
+}
+func (stdLogger) ErrorContext(ctx context.Context, message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printc(ctx, 1, ERROR, message, kvs...)
+}
+func (stdLogger) PanicContext(ctx context.Context, message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printc(ctx, 1, PANIC, message, kvs...)
+}
+func (stdLogger) FatalContext(ctx context.Context, message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printc(ctx, 1, FATAL, message, kvs...)
+}
+
+func (stdLogger) Print(depth int, level Level, args ...interface{}) {
+	zaplog.StdLogger().Print(depth+1, level, args...)
+}
+func (stdLogger) Printf(depth int, level Level, format string, args ...interface{}) {
+	zaplog.StdLogger().Printf(depth+1, level, format, args...)
+}
+func (stdLogger) Printw(depth int, level Level, message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printw(depth+1, level, message, kvs...)
+}
+func (stdLogger) Printc(ctx context.Context, depth int, level Level, message string, kvs ...interface{}) {
+	zaplog.StdLogger().Printc(ctx, depth+1, level, message, kvs...)
+}
+func (stdLogger) PrintEntry(ctx context.Context, t time.Time, pc uintptr, level Level, message string, kvs ...interface{}) {
+	zaplog.StdLogger().PrintEntry(ctx, t, pc, level, message, kvs...)
+}
+
+// Sync 同步已经创建的 zaplog.StdLogger(), 尚未创建时不创建, 返回 nil
+func (stdLogger) Sync() error {
+	if l, ok := zaplog.CreatedStdLogger(); ok {
+		return l.Sync()
+	}
+	return nil
+}
+
+// Close 关闭已经创建的 zaplog.StdLogger(), 使 SetLogger 的 CloseOld 对默认的日志对象同样有效
+func (stdLogger) Close() error {
+	if l, ok := zaplog.CreatedStdLogger(); ok {
+		return l.Close()
+	}
+	return nil
+}
+
+// loggerHolder 包装全局日志对象, 使 logging 中保存的值的类型保持一致
+type loggerHolder struct {
+	logger Logger
+}
+
+var (
+	setMu   sync.Mutex   // 串行化 SetLogger
+	logging atomic.Value // *loggerHolder, 读取时无需加锁
+)
+
+// current 返回当前的全局日志对象, 未设置时返回 zaplog.StdLogger()
+func current() Logger {
+	if h, ok := logging.Load().(*loggerHolder); ok && h.logger != nil {
+		return h.logger
+	}
+	return zaplog.StdLogger()
+}
+
+// loaded 返回设置的全局日志对象, 未设置时返回 stdLogger{}, 不创建 zaplog.StdLogger()
+func loaded() Logger {
+	if h, ok := logging.Load().(*loggerHolder); ok && h.logger != nil {
+		return h.logger
+	}
+	return stdLogger{}
+}
+
+// swap 替换全局日志对象, 全局日志对象未设置时返回 stdLogger{}, 不创建 zaplog.StdLogger();
+// l 为 stdLogger{} 时恢复为未设置的状态
+func swap(l Logger) (old Logger) {
+	setMu.Lock()
+	defer setMu.Unlock()
+
+	old = loaded()
+	if _, ok := l.(stdLogger); ok {
+		l = nil
+	}
+	logging.Store(&loggerHolder{logger: l})
+	return old
+}
+
+type setOptions struct {
+	closeOld bool
+	drain    time.Duration
+}
+
+type SetOption func(*setOptions)
+
+// CloseOld 设置 SetLogger 在替换后关闭之前的日志对象;
+// drain 为等待仍在使用旧日志对象的调用结束的时间, 大于 0 时在后台等待 drain 后关闭
+func CloseOld(drain time.Duration) SetOption {
+	return func(o *setOptions) {
+		o.closeOld = true
+		o.drain = drain
+	}
+}
+
+// SetLogger 设置全局日志对象, 返回之前的全局日志对象;
+// 未设置过时返回代表 zaplog.StdLogger() 的日志对象, 不会因此创建 zaplog.StdLogger()
+func SetLogger(l Logger, opts ...SetOption) (old Logger) {
+	var o setOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-func SetLogger(l Logger) (old Logger) {
-	old = logging
 	if l == nil {
 		l = nopLogger{}
 	}
-	logging = l
+	old = swap(l)
+
+	if o.closeOld && !sameLogger(old, l) {
+		if o.drain > 0 {
+			time.AfterFunc(o.drain, func() { closeLogger(old) })
+		} else {
+			closeLogger(old)
+		}
+	}
 	return old
 }
 
+// sameLogger 判断 a 与 b 是否为同一个日志对象, 动态类型不可比较(如包含 map 的结构体)时视为不同
+func sameLogger(a, b Logger) bool {
+	t := reflect.TypeOf(a)
+	return t != nil && t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+func closeLogger(l Logger) {
+	if err := closeOrSync(l); err != nil {
+		fmt.Fprintf(os.Stderr, "tlog: close logger: %v\n", err)
+	}
+}
+
+// closeOrSync 关闭实现了 io.Closer 的日志对象, 否则同步实现了 Sync 的日志对象
+func closeOrSync(l Logger) error {
+	if c, ok := l.(io.Closer); ok {
+		return c.Close()
+	}
+	return syncLogger(l)
+}
+
+func syncLogger(l Logger) error {
+	if s, ok := l.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Sync 同步当前日志对象的输出, 日志对象未实现 Sync 方法时返回 nil
+func Sync() error {
+	return syncLogger(loaded())
+}
+
+// Close 关闭当前日志对象, 日志对象未实现 io.Closer 时调用 Sync, 用于程序退出前的清理
+func Close() error {
+	return closeOrSync(loaded())
+}
+
 func GetLogger() Logger {
-	return logging
+	return current()
+}
+
+// ReplaceLogger 替换全局日志对象, 返回恢复之前的全局日志对象的函数, 如:
+//
+//	defer tlog.ReplaceLogger(logger)()
+func ReplaceLogger(l Logger) (restore func()) {
+	old := SetLogger(l)
+	return func() {
+		SetLogger(old)
+	}
 }
 
 func Named(name string) Logger {
-	return logging.Named(name)
+	return current().Named(name)
 }
 
 func WithArgs(args ...interface{}) Logger {
-	return logging.WithArgs(args...)
+	return current().WithArgs(args...)
 }
 
 func WithContext(ctx context.Context) Logger {
-	return logging.WithContext(ctx)
+	return current().WithContext(ctx)
 }
 
 func Debug(args ...interface{}) {
-	logging.Print(1, DEBUG, args...)
+	current().Print(1, DEBUG, args...)
 }
 
 func Debugf(format string, args ...interface{}) {
-	logging.Printf(1, DEBUG, format, args...)
+	current().Printf(1, DEBUG, format, args...)
 }
 
 func Debugw(message string, kvs ...interface{}) {
-	logging.Printw(1, DEBUG, message, kvs...)
+	current().Printw(1, DEBUG, message, kvs...)
 }
 
 func Info(args ...interface{}) {
-	logging.Print(1, INFO, args...)
+	current().Print(1, INFO, args...)
 }
 
 func Infof(format string, args ...interface{}) {
-	logging.Printf(1, INFO, format, args...)
+	current().Printf(1, INFO, format, args...)
 }
 
 func Infow(message string, kvs ...interface{}) {
-	logging.Printw(1, INFO, message, kvs...)
+	current().Printw(1, INFO, message, kvs...)
 }
 
 func Warn(args ...interface{}) {
-	logging.Print(1, WARN, args...)
+	current().Print(1, WARN, args...)
 }
 
 func Warnf(format string, args ...interface{}) {
-	logging.Printf(1, WARN, format, args...)
+	current().Printf(1, WARN, format, args...)
 }
 
 func Warnw(message string, kvs ...interface{}) {
-	logging.Printw(1, WARN, message, kvs...)
+	current().Printw(1, WARN, message, kvs...)
 }
 
 func Error(args ...interface{}) {
-	logging.Print(1, ERROR, args...)
+	current().Print(1, ERROR, args...)
 }
 
 func Errorf(format string, args ...interface{}) {
-	logging.Printf(1, ERROR, format, args...)
+	current().Printf(1, ERROR, format, args...)
 }
 
 func Errorw(message string, kvs ...interface{}) {
-	logging.Printw(1, ERROR, message, kvs...)
+	current().Printw(1, ERROR, message, kvs...)
 }
 
 func Panic(args ...interface{}) {
-	logging.Print(1, PANIC, args...)
+	current().Print(1, PANIC, args...)
 }
 
 func Panicf(format string, args ...interface{}) {
-	logging.Printf(1, PANIC, format, args...)
+	current().Printf(1, PANIC, format, args...)
 }
 
 func Panicw(message string, kvs ...interface{}) {
-	logging.Printw(1, PANIC, message, kvs...)
+	current().Printw(1, PANIC, message, kvs...)
 }
 
 func Fatal(args ...interface{}) {
-	logging.Print(1, FATAL, args...)
+	current().Print(1, FATAL, args...)
 }
 
 func Fatalf(format string, args ...interface{}) {
-	logging.Printf(1, FATAL, format, args...)
+	current().Printf(1, FATAL, format, args...)
 }
 
 func Fatalw(message string, kvs ...interface{}) {
-	logging.Printw(1, FATAL, message, kvs...)
+	current().Printw(1, FATAL, message, kvs...)
+}
+
+func DebugContext(ctx context.Context, message string, kvs ...interface{}) {
+	current().Printc(ctx, 1, DEBUG, message, kvs...)
+}
+
+func InfoContext(ctx context.Context, message string, kvs ...interface{}) {
+	current().Printc(ctx, 1, INFO, message, kvs...)
+}
+
+func WarnContext(ctx context.Context, message string, kvs ...interface{}) {
+	current().Printc(ctx, 1, WARN, message, kvs...)
+}
+
+func ErrorContext(ctx context.Context, message string, kvs ...interface{}) {
+	current().Printc(ctx, 1, ERROR, message, kvs...)
+}
+
+func PanicContext(ctx context.Context, message string, kvs ...interface{}) {
+	current().Printc(ctx, 1, PANIC, message, kvs...)
+}
+
+func FatalContext(ctx context.Context, message string, kvs ...interface{}) {
+	current().Printc(ctx, 1, FATAL, message, kvs...)
 }
 
 func Print(depth int, level Level, args ...interface{}) {
-	logging.Print(depth+1, level, args...)
+	current().Print(depth+1, level, args...)
 }
 
 func Printf(depth int, level Level, format string, args ...interface{}) {
-	logging.Printf(depth+1, level, format, args...)
+	current().Printf(depth+1, level, format, args...)
 }
 
 func Printw(depth int, level Level, message string, kvs ...interface{}) {
-	logging.Printw(depth+1, level, message, kvs...)
+	current().Printw(depth+1, level, message, kvs...)
+}
+
+func Printc(ctx context.Context, depth int, level Level, message string, kvs ...interface{}) {
+	current().Printc(ctx, depth+1, level, message, kvs...)
 }
@@ -2,9 +2,16 @@ package tlog_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ironzhang/tlog"
 	"github.com/ironzhang/tlog/iface"
@@ -55,6 +62,16 @@ func PrintAccessLogs(name string) {
 	logger.Printf(0, iface.INFO, "Printf %s", name)
 	logger.Printw(0, iface.INFO, "Printw", "name", name)
 
+	ctx := tlog.ContextWithArgs(context.Background(), "name", name)
+	logger.DebugContext(ctx, "DebugContext")
+	logger.InfoContext(ctx, "InfoContext")
+	logger.WarnContext(ctx, "WarnContext")
+	logger.ErrorContext(ctx, "ErrorContext")
+	RecoverPanic(func() {
+		logger.PanicContext(ctx, "PanicContext")
+	})
+	logger.Printc(ctx, 0, iface.INFO, "Printc")
+
 	logger.WithArgs("name", name).Info("with args")
 	logger.WithContext(context.Background()).Info("with context")
 	logger.WithArgs("name", name).WithContext(context.Background()).Info("with args and with context")
@@ -91,6 +108,16 @@ func PrintLogs(name string) {
 	tlog.Printf(0, iface.INFO, "Printf %s", name)
 	tlog.Printw(0, iface.INFO, "Printw", "name", name)
 
+	ctx := tlog.ContextWithArgs(context.Background(), "name", name)
+	tlog.DebugContext(ctx, "DebugContext")
+	tlog.InfoContext(ctx, "InfoContext")
+	tlog.WarnContext(ctx, "WarnContext")
+	tlog.ErrorContext(ctx, "ErrorContext")
+	RecoverPanic(func() {
+		tlog.PanicContext(ctx, "PanicContext")
+	})
+	tlog.Printc(ctx, 0, iface.INFO, "Printc")
+
 	tlog.WithArgs("name", name).Info("with args")
 	tlog.WithContext(context.Background()).Info("with context")
 	tlog.WithArgs("name", name).WithContext(context.Background()).Info("with args and with context")
@@ -119,3 +146,225 @@ func TestLogger(t *testing.T) {
 	PrintLogs("ExampleLogger")
 	tlog.SetLogger(zaplog.StdLogger())
 }
+
+type tSyncLogger struct {
+	tlog.Logger
+	syncs int
+}
+
+func (p *tSyncLogger) Sync() error {
+	p.syncs++
+	return nil
+}
+
+type tCloseLogger struct {
+	tSyncLogger
+	closes int
+	closed chan struct{}
+	err    error
+}
+
+func (p *tCloseLogger) Close() error {
+	p.closes++
+	if p.closed != nil {
+		close(p.closed)
+	}
+	return p.err
+}
+
+func TestSyncClose(t *testing.T) {
+	sl := &tSyncLogger{Logger: tlog.GetLogger()}
+	cl := &tCloseLogger{tSyncLogger: tSyncLogger{Logger: tlog.GetLogger()}}
+	old := tlog.SetLogger(sl)
+	defer tlog.SetLogger(old)
+
+	if err := tlog.Sync(); err != nil {
+		t.Errorf("sync: %v", err)
+	}
+	if err := tlog.Close(); err != nil {
+		t.Errorf("close: %v", err)
+	}
+	if got, want := sl.syncs, 2; got != want {
+		t.Errorf("syncs: got %d, want %d", got, want)
+	}
+
+	tlog.SetLogger(cl)
+	tlog.Sync()
+	cl.err = errors.New("closed")
+	if err := tlog.Close(); err != cl.err {
+		t.Errorf("close: got %v, want %v", err, cl.err)
+	}
+	if cl.syncs != 1 || cl.closes != 1 {
+		t.Errorf("syncs, closes: got %d, %d, want 1, 1", cl.syncs, cl.closes)
+	}
+
+	tlog.SetLogger(nil)
+	if err := tlog.Sync(); err != nil {
+		t.Errorf("sync: %v", err)
+	}
+	if err := tlog.Close(); err != nil {
+		t.Errorf("close: %v", err)
+	}
+}
+
+func TestSetLoggerCloseOld(t *testing.T) {
+	sl := &tSyncLogger{Logger: tlog.GetLogger()}
+	cl := &tCloseLogger{tSyncLogger: tSyncLogger{Logger: tlog.GetLogger()}}
+	old := tlog.SetLogger(cl)
+	defer tlog.SetLogger(old)
+
+	tlog.SetLogger(cl, tlog.CloseOld(0))
+	if got, want := cl.closes, 0; got != want {
+		t.Errorf("same logger closes: got %d, want %d", got, want)
+	}
+
+	if got := tlog.SetLogger(sl, tlog.CloseOld(0)); got != cl {
+		t.Errorf("old logger: got %v, want %v", got, cl)
+	}
+	if got, want := cl.closes, 1; got != want {
+		t.Errorf("closes: got %d, want %d", got, want)
+	}
+
+	tlog.SetLogger(cl)
+	if got, want := sl.syncs, 0; got != want {
+		t.Errorf("syncs: got %d, want %d", got, want)
+	}
+
+	cl.closed = make(chan struct{})
+	tlog.SetLogger(sl, tlog.CloseOld(10*time.Millisecond))
+	select {
+	case <-cl.closed:
+	case <-time.After(time.Second):
+		t.Fatalf("old logger is not closed after drain")
+	}
+	if got, want := cl.closes, 2; got != want {
+		t.Errorf("closes: got %d, want %d", got, want)
+	}
+
+	tlog.SetLogger(cl, tlog.CloseOld(0))
+	if got, want := sl.syncs, 1; got != want {
+		t.Errorf("syncs: got %d, want %d", got, want)
+	}
+}
+
+// tMapLogger 是不可比较的日志对象
+type tMapLogger struct {
+	iface.Logger
+	fields map[string]interface{}
+}
+
+func TestSetLoggerUncomparable(t *testing.T) {
+	defer tlog.ReplaceLogger(tlog.GetLogger())()
+
+	l := tMapLogger{Logger: tlog.GetLogger(), fields: map[string]interface{}{}}
+	tlog.SetLogger(l, tlog.CloseOld(0))
+	tlog.SetLogger(l, tlog.CloseOld(0))
+	tlog.SetLogger(tMapLogger{Logger: l.Logger}, tlog.CloseOld(0))
+}
+
+func TestReplaceLogger(t *testing.T) {
+	old := tlog.GetLogger()
+	logger, logs := newObservedLogger()
+
+	restore := tlog.ReplaceLogger(logger)
+	if tlog.GetLogger() != logger {
+		t.Fatalf("logger is not replaced")
+	}
+	tlog.Info("replaced")
+	restore()
+	if tlog.GetLogger() != old {
+		t.Fatalf("logger is not restored")
+	}
+	tlog.Info("restored")
+
+	if got, want := logs.Len(), 1; got != want {
+		t.Errorf("logs: got %d, want %d", got, want)
+	}
+}
+
+func TestSetLoggerDefault(t *testing.T) {
+	defer tlog.ReplaceLogger(tlog.GetLogger())()
+	tlog.ResetLogger()
+
+	logger, logs := newObservedLogger()
+	old := tlog.SetLogger(logger, tlog.CloseOld(0))
+	if _, ok := old.(*zaplog.Logger); ok {
+		t.Fatalf("old logger is the std logger")
+	}
+	tlog.Info("replaced")
+	if got, want := logs.Len(), 1; got != want {
+		t.Errorf("logs: got %d, want %d", got, want)
+	}
+
+	tlog.SetLogger(old)
+	if tlog.GetLogger() != iface.Logger(zaplog.StdLogger()) {
+		t.Errorf("logger is not restored to the std logger")
+	}
+	old.Infow("std logger", "k", 1)
+}
+
+func TestSetLoggerConcurrently(t *testing.T) {
+	l1, logs1 := newObservedLogger()
+	l2, logs2 := newObservedLogger()
+	defer tlog.ReplaceLogger(l1)()
+
+	const n = 1000
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				tlog.Infow("hello", "j", j)
+				tlog.Named("access").Info("hello")
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < n; j++ {
+			if j%2 == 0 {
+				tlog.SetLogger(l2)
+			} else {
+				tlog.ReplaceLogger(l1)
+			}
+		}
+	}()
+	wg.Wait()
+
+	if got, want := logs1.Len()+logs2.Len(), 4*n*2; got != want {
+		t.Errorf("logs: got %d, want %d", got, want)
+	}
+}
+
+// TestSetLoggerCloseStd 在子进程中使用 TLOG_OUTPUT 创建输出到文件的 StdLogger,
+// SetLogger 的 CloseOld 应关闭已经创建的 StdLogger, 使缓冲的日志写入文件
+func TestSetLoggerCloseStd(t *testing.T) {
+	if os.Getenv("TLOG_TEST_CLOSE_STD") == "1" {
+		tlog.ResetLogger()
+		tlog.Info("close std")
+		tlog.SetLogger(nil, tlog.CloseOld(0))
+		return
+	}
+
+	dir, err := ioutil.TempDir("", "tlog")
+	if err != nil {
+		t.Fatalf("temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "app.log")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSetLoggerCloseStd$")
+	cmd.Env = append(os.Environ(), "TLOG_TEST_CLOSE_STD=1", zaplog.EnvOutput+"=rfile://localhost"+file)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if !strings.Contains(string(data), "close std") {
+		t.Errorf("file: got %q, want it to contain %q", data, "close std")
+	}
+}
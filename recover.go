@@ -0,0 +1,138 @@
+package tlog
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+type recoverer struct {
+	message string
+	repanic bool
+	handler func(r interface{})
+}
+
+type RecoverOption func(*recoverer)
+
+// SetPanicMessage 设置输出 panic 时的日志消息, 默认为 "panic recovered"
+func SetPanicMessage(message string) RecoverOption {
+	return func(p *recoverer) {
+		p.message = message
+	}
+}
+
+// SetRepanic 设置输出日志后是否重新 panic, 默认不重新 panic
+func SetRepanic(repanic bool) RecoverOption {
+	return func(p *recoverer) {
+		p.repanic = repanic
+	}
+}
+
+// SetPanicHandler 设置输出日志后调用的函数, 如返回错误响应或增加监控计数
+func SetPanicHandler(handler func(r interface{})) RecoverOption {
+	return func(p *recoverer) {
+		p.handler = handler
+	}
+}
+
+func newRecoverer(opts []RecoverOption) *recoverer {
+	p := &recoverer{message: "panic recovered"}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Recover 恢复 panic, 并以 PANIC 级别输出 panic 的值, 协程调用栈及 logger 绑定的字段,
+// 须直接用于 defer, 如 defer tlog.Recover(logger); logger 为 nil 时使用 GetLogger()
+func Recover(logger Logger, opts ...RecoverOption) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	newRecoverer(opts).recovered(logger, r)
+}
+
+func (p *recoverer) recovered(logger Logger, r interface{}) {
+	if logger == nil {
+		logger = GetLogger()
+	}
+	p.log(logger, r)
+	if p.handler != nil {
+		p.handler(r)
+	}
+	if p.repanic {
+		panic(r)
+	}
+}
+
+// log 输出 panic, 日志的调用位置为引发 panic 的函数;
+// PANIC 级别的日志在输出后会 panic, 这里恢复该 panic
+func (p *recoverer) log(logger Logger, r interface{}) {
+	defer func() {
+		recover()
+	}()
+	logger.Printw(panicDepth(), PANIC, p.message, "panic", r, "stack", string(debug.Stack()))
+}
+
+// panicDepth 返回 log 到引发 panic 的函数的调用深度
+func panicDepth() int {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	panicking := false
+	for depth := 0; ; depth++ {
+		frame, more := frames.Next()
+		if panicking && !strings.HasPrefix(frame.Function, "runtime.") {
+			return depth
+		}
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		}
+		if !more {
+			return 0
+		}
+	}
+}
+
+// Go 在新的协程中执行 fn, fn 中的 panic 被恢复并输出到 GetLogger()
+func Go(fn func(), opts ...RecoverOption) {
+	go func() {
+		defer Recover(nil, opts...)
+		fn()
+	}()
+}
+
+// GoContext 在新的协程中执行 fn, fn 中的 panic 被恢复并输出到 FromContext(ctx).WithContext(ctx),
+// 日志中包含 ctx 携带的字段, 如请求 ID
+func GoContext(ctx context.Context, fn func(ctx context.Context), opts ...RecoverOption) {
+	go func() {
+		defer Recover(FromContext(ctx).WithContext(ctx), opts...)
+		fn(ctx)
+	}()
+}
+
+// SafeHandler 恢复 h 中的 panic, 输出到 FromContext(r.Context()).WithContext(r.Context()),
+// 不重新 panic 时返回 500 响应; http.ErrAbortHandler 用于中止请求, 不输出日志并重新 panic
+func SafeHandler(h http.Handler, opts ...RecoverOption) http.Handler {
+	p := newRecoverer(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			ctx := r.Context()
+			if !p.repanic {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			p.recovered(FromContext(ctx).WithContext(ctx), v)
+		}()
+		h.ServeHTTP(w, r)
+	})
+}
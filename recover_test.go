@@ -0,0 +1,141 @@
+package tlog_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ironzhang/tlog"
+)
+
+func checkPanicLog(t *testing.T, logs *observer.ObservedLogs, value interface{}, function string) observer.LoggedEntry {
+	t.Helper()
+	entries := logs.TakeAll()
+	if got, want := len(entries), 1; got != want {
+		t.Fatalf("entries: got %d, want %d", got, want)
+	}
+	e := entries[0]
+	assert.Equal(t, zapcore.PanicLevel, e.Level, "unexpected level")
+	assert.Equal(t, "panic recovered", e.Message, "unexpected message")
+	fields := e.ContextMap()
+	assert.Equal(t, value, fields["panic"], "unexpected panic value")
+	assert.Contains(t, fields["stack"], function, "unexpected stack")
+	assert.Equal(t, "recover_test.go", filepath.Base(e.Caller.File), "unexpected caller")
+	return e
+}
+
+func panicWith(logger tlog.Logger, v interface{}, opts ...tlog.RecoverOption) {
+	defer tlog.Recover(logger, opts...)
+	panic(v)
+}
+
+func TestRecover(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	panicWith(logger, "boom")
+	checkPanicLog(t, logs, "boom", "tlog_test.panicWith")
+
+	func() {
+		defer tlog.Recover(logger.WithArgs("request", "r1"))
+		var m map[string]int
+		m["k"] = 1
+	}()
+	e := checkPanicLog(t, logs, "assignment to entry in nil map", "tlog_test.TestRecover")
+	assert.Equal(t, "r1", e.ContextMap()["request"], "unexpected bound field")
+
+	func() {
+		defer tlog.Recover(logger)
+	}()
+	assert.Equal(t, 0, logs.Len(), "unexpected logs")
+}
+
+func TestRecoverOptions(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	var recovered interface{}
+	panicWith(logger, "boom", tlog.SetPanicMessage("crashed"), tlog.SetPanicHandler(func(r interface{}) {
+		recovered = r
+	}))
+	assert.Equal(t, "boom", recovered, "unexpected recovered value")
+	entries := logs.TakeAll()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "crashed", entries[0].Message, "unexpected message")
+	}
+
+	assert.PanicsWithValue(t, "boom", func() {
+		panicWith(logger, "boom", tlog.SetRepanic(true))
+	}, "unexpected repanic")
+	checkPanicLog(t, logs, "boom", "tlog_test.panicWith")
+}
+
+func TestRecoverGlobal(t *testing.T) {
+	logger, logs := newObservedLogger()
+	old := tlog.SetLogger(logger)
+	defer tlog.SetLogger(old)
+
+	panicWith(nil, "boom")
+	checkPanicLog(t, logs, "boom", "tlog_test.panicWith")
+}
+
+func TestGo(t *testing.T) {
+	logger, logs := newObservedLogger()
+	old := tlog.SetLogger(logger)
+	defer tlog.SetLogger(old)
+
+	done := make(chan struct{})
+	tlog.Go(func() {
+		panic("boom")
+	}, tlog.SetPanicHandler(func(interface{}) { close(done) }))
+	<-done
+	checkPanicLog(t, logs, "boom", "tlog_test.TestGo")
+
+	done = make(chan struct{})
+	ctx := tlog.NewContext(tlog.ContextWithArgs(context.Background(), "request", "r1"), logger)
+	tlog.GoContext(ctx, func(ctx context.Context) {
+		panic("boom")
+	}, tlog.SetPanicHandler(func(interface{}) { close(done) }))
+	<-done
+	e := checkPanicLog(t, logs, "boom", "tlog_test.TestGo")
+	assert.Equal(t, "r1", e.ContextMap()["request"], "unexpected context field")
+}
+
+func TestSafeHandler(t *testing.T) {
+	logger, logs := newObservedLogger()
+	h := tlog.SafeHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/abort" {
+			panic(http.ErrAbortHandler)
+		}
+		if r.URL.Path == "/panic" {
+			panic("boom")
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	newRequest := func(path string) *http.Request {
+		r := httptest.NewRequest("GET", path, nil)
+		ctx := tlog.NewContext(tlog.ContextWithArgs(r.Context(), "request", "r1"), logger)
+		return r.WithContext(ctx)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest("/"))
+	assert.Equal(t, http.StatusOK, w.Code, "unexpected status code")
+	assert.Equal(t, 0, logs.Len(), "unexpected logs")
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest("/panic"))
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "unexpected status code")
+	e := checkPanicLog(t, logs, "boom", "tlog_test.TestSafeHandler")
+	assert.Equal(t, "r1", e.ContextMap()["request"], "unexpected context field")
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), newRequest("/abort"))
+	}, "unexpected abort")
+	assert.Equal(t, 0, logs.Len(), "unexpected logs")
+}
@@ -0,0 +1,39 @@
+package tlog
+
+import (
+	"bytes"
+	"log"
+)
+
+// 标准库 log 的输出方法经过 Output 方法调用 Write
+const stdLogDepth = 3
+
+type stdWriter struct {
+	logger Logger
+	level  Level
+}
+
+func (w *stdWriter) Write(b []byte) (int, error) {
+	msg := string(bytes.TrimSuffix(b, []byte("\n")))
+	w.logger.Printw(stdLogDepth, w.level, msg)
+	return len(b), nil
+}
+
+// NewStdLog 返回以 level 级别输出到 logger 的标准库 log.Logger, 可用于 http.Server.ErrorLog 等
+func NewStdLog(logger Logger, level Level) *log.Logger {
+	return log.New(&stdWriter{logger: logger, level: level}, "", 0)
+}
+
+// RedirectStdLog 将标准库 log 包的输出以 level 级别重定向到 logger,
+// 前缀及 flags 由 logger 的编码器处理, 重定向期间被清空; 返回恢复原设置的函数
+func RedirectStdLog(logger Logger, level Level) (restore func()) {
+	flags, prefix, output := log.Flags(), log.Prefix(), log.Writer()
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(&stdWriter{logger: logger, level: level})
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(output)
+	}
+}
@@ -0,0 +1,72 @@
+package tlog_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ironzhang/tlog"
+	"github.com/ironzhang/tlog/zaplog/zlogger"
+)
+
+func newObservedLogger() (tlog.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zlogger.New("", core, nil, zap.AddCaller()), logs
+}
+
+func checkStdLogs(t *testing.T, logs *observer.ObservedLogs, level zapcore.Level, messages ...string) {
+	entries := logs.TakeAll()
+	if got, want := len(entries), len(messages); got != want {
+		t.Fatalf("entries: got %d, want %d", got, want)
+	}
+	for i, e := range entries {
+		assert.Equal(t, level, e.Level, "unexpected level")
+		assert.Equal(t, messages[i], e.Message, "unexpected message")
+		assert.Regexp(t, `stdlog_test.go`, e.Caller.String(), "unexpected caller")
+	}
+}
+
+func TestNewStdLog(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	std := tlog.NewStdLog(logger, tlog.WARN)
+	std.Print("print")
+	std.Printf("printf %d", 1)
+	std.Println("println")
+	checkStdLogs(t, logs, zapcore.WarnLevel, "print", "printf 1", "println")
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.SetPrefix("prefix ")
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	logger, logs := newObservedLogger()
+	restore := tlog.RedirectStdLog(logger, tlog.ERROR)
+	log.Print("print")
+	log.Printf("printf %d", 1)
+	log.Println("line1\nline2")
+	checkStdLogs(t, logs, zapcore.ErrorLevel, "print", "printf 1", "line1\nline2")
+
+	restore()
+	log.Print("restored")
+	if got, want := log.Flags(), log.LstdFlags|log.Lshortfile; got != want {
+		t.Errorf("flags: got %d, want %d", got, want)
+	}
+	if got, want := log.Prefix(), "prefix "; got != want {
+		t.Errorf("prefix: got %q, want %q", got, want)
+	}
+	assert.Regexp(t, `^prefix .*stdlog_test.go:\d+: restored\n$`, buf.String(), "unexpected std output")
+	assert.Equal(t, 0, logs.Len(), "unexpected log entries after restore")
+
+	log.SetOutput(os.Stderr)
+	log.SetPrefix("")
+	log.SetFlags(log.LstdFlags)
+}
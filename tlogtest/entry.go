@@ -0,0 +1,133 @@
+package tlogtest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zbase"
+)
+
+// Entry 是一条已记录的日志, Fields 中的 iface.Valuer 已计算为最终的值
+type Entry struct {
+	Time    time.Time
+	Level   iface.Level
+	Name    string
+	Message string
+	Caller  string
+	Fields  map[string]interface{}
+}
+
+func newEntry(e observer.LoggedEntry) Entry {
+	fields := e.ContextMap()
+	for k, v := range fields {
+		fields[k] = iface.Resolve(v)
+	}
+	caller := ""
+	if e.Caller.Defined {
+		caller = e.Caller.TrimmedPath()
+	}
+	return Entry{
+		Time:    e.Time,
+		Level:   zbase.LogLevel(e.Level),
+		Name:    e.LoggerName,
+		Message: e.Message,
+		Caller:  caller,
+		Fields:  fields,
+	}
+}
+
+// HasField 判断日志是否包含字段 key, 且值与 value 相同; 值通过 fmt.Sprint 比较, 如 int(1) 与 int64(1) 相同
+func (e Entry) HasField(key string, value interface{}) bool {
+	v, ok := e.Fields[key]
+	if !ok {
+		return false
+	}
+	return fmt.Sprint(v) == fmt.Sprint(iface.Resolve(value))
+}
+
+// Match 判断日志是否匹配级别, 消息及 kvs 给出的全部字段
+func (e Entry) Match(level iface.Level, message string, kvs ...interface{}) bool {
+	if e.Level != level || e.Message != message {
+		return false
+	}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if !e.HasField(fmt.Sprint(kvs[i]), kvs[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e Entry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\t", e.Level)
+	if e.Name != "" {
+		fmt.Fprintf(&b, "%s\t", e.Name)
+	}
+	b.WriteString(e.Message)
+	if len(e.Fields) > 0 {
+		fmt.Fprintf(&b, "\t%v", e.Fields)
+	}
+	return b.String()
+}
+
+// Entries 是一组日志, 各个 Filter 方法返回满足条件的日志
+type Entries []Entry
+
+func (p Entries) Len() int {
+	return len(p)
+}
+
+func (p Entries) Filter(f func(Entry) bool) Entries {
+	var entries Entries
+	for _, e := range p {
+		if f(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func (p Entries) FilterLevel(level iface.Level) Entries {
+	return p.Filter(func(e Entry) bool { return e.Level == level })
+}
+
+// FilterMinLevel 返回级别不低于 level 的日志
+func (p Entries) FilterMinLevel(level iface.Level) Entries {
+	return p.Filter(func(e Entry) bool { return e.Level >= level })
+}
+
+func (p Entries) FilterMessage(message string) Entries {
+	return p.Filter(func(e Entry) bool { return e.Message == message })
+}
+
+func (p Entries) FilterMessageSnippet(snippet string) Entries {
+	return p.Filter(func(e Entry) bool { return strings.Contains(e.Message, snippet) })
+}
+
+func (p Entries) FilterField(key string, value interface{}) Entries {
+	return p.Filter(func(e Entry) bool { return e.HasField(key, value) })
+}
+
+func (p Entries) FilterFieldKey(key string) Entries {
+	return p.Filter(func(e Entry) bool {
+		_, ok := e.Fields[key]
+		return ok
+	})
+}
+
+func (p Entries) FilterName(name string) Entries {
+	return p.Filter(func(e Entry) bool { return e.Name == name })
+}
+
+func (p Entries) Messages() []string {
+	messages := make([]string, 0, len(p))
+	for _, e := range p {
+		messages = append(messages, e.Message)
+	}
+	return messages
+}
@@ -0,0 +1,210 @@
+package tlogtest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog"
+	"github.com/ironzhang/tlog/zaplog/zsink"
+)
+
+// UpdateGoldenEnv 为非空时与 -tlogtest.update 参数相同, 可用于同时更新多个包的 golden 文件
+const UpdateGoldenEnv = "TLOG_UPDATE_GOLDEN"
+
+// 参数名带包名前缀, 避免与使用方定义的 -update 参数冲突
+var updateFlag = flag.Bool("tlogtest.update", false, "update the testdata/*.golden files of tlogtest.Golden")
+
+func updating() bool {
+	return *updateFlag || os.Getenv(UpdateGoldenEnv) != ""
+}
+
+// GoldenTime 是 Golden 创建的日志对象输出的日志时间
+var GoldenTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// GoldenClock 返回 GoldenTime, 自行创建日志对象时可通过 zaplog.SetClock 设置
+func GoldenClock() time.Time {
+	return GoldenTime
+}
+
+const goldenScheme = "tlogtest"
+
+var (
+	goldenMu    sync.Mutex
+	goldenSeq   int
+	goldenFiles = make(map[string]*GoldenFile)
+)
+
+func init() {
+	err := zsink.RegisterSink(goldenScheme, func(u *url.URL) (zap.Sink, error) {
+		goldenMu.Lock()
+		defer goldenMu.Unlock()
+		g, ok := goldenFiles[u.Host]
+		if !ok {
+			return nil, fmt.Errorf("golden file %q is not found", u.Host)
+		}
+		return g, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GoldenFile 收集日志输出, 并与 testdata/<name>.golden 文件比较;
+// 使用 -tlogtest.update 参数或设置 TLOG_UPDATE_GOLDEN 环境变量运行测试时以日志输出更新 golden 文件
+type GoldenFile struct {
+	t    TestingT
+	name string
+	id   string
+	once sync.Once
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Golden 返回名为 name 的 GoldenFile, 测试结束时(t.Cleanup)或调用 Verify 时比较输出
+func Golden(t TestingT, name string) *GoldenFile {
+	goldenMu.Lock()
+	goldenSeq++
+	g := &GoldenFile{t: t, name: name, id: strconv.Itoa(goldenSeq)}
+	goldenFiles[g.id] = g
+	goldenMu.Unlock()
+
+	if c, ok := t.(interface{ Cleanup(func()) }); ok {
+		c.Cleanup(g.Verify)
+	}
+	return g
+}
+
+// URL 返回写入 GoldenFile 的输出地址, 可用于自定义的 zaplog.Config
+func (g *GoldenFile) URL() string {
+	return goldenScheme + "://" + g.id
+}
+
+// Config 返回写入 GoldenFile 的配置, 调用位置只包含文件名及行号
+func (g *GoldenFile) Config(encoding string) zaplog.Config {
+	encoder := zaplog.NewJSONEncoderConfig()
+	if encoding == "console" {
+		encoder = zaplog.NewConsoleEncoderConfig()
+	}
+	encoder.EncodeCaller = zaplog.BaseCallerEncoder
+	return zaplog.Config{
+		Level: iface.DEBUG,
+		Cores: []zaplog.CoreConfig{
+			{
+				Name:     "Golden",
+				Encoding: encoding,
+				Encoder:  encoder,
+				MinLevel: iface.DEBUG,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{g.URL()},
+			},
+		},
+		Loggers: []zaplog.LoggerConfig{
+			{
+				Cores: []string{"Golden"},
+			},
+		},
+	}
+}
+
+// Logger 返回使用 Config(encoding) 创建的日志对象, 日志时间固定为 GoldenTime
+func (g *GoldenFile) Logger(encoding string) *zaplog.Logger {
+	g.t.Helper()
+	logger, err := zaplog.New(g.Config(encoding), zaplog.SetClock(GoldenClock))
+	if err != nil {
+		g.t.Errorf("new golden logger: %v", err)
+		g.t.FailNow()
+	}
+	return logger
+}
+
+func (g *GoldenFile) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.buf.Write(p)
+}
+
+func (g *GoldenFile) Sync() error {
+	return nil
+}
+
+func (g *GoldenFile) Close() error {
+	return nil
+}
+
+// Bytes 返回已收集的输出
+func (g *GoldenFile) Bytes() []byte {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]byte(nil), g.buf.Bytes()...)
+}
+
+// Path 返回 golden 文件的路径
+func (g *GoldenFile) Path() string {
+	return filepath.Join("testdata", g.name+".golden")
+}
+
+// Verify 比较输出与 golden 文件, 多次调用只比较一次
+func (g *GoldenFile) Verify() {
+	g.t.Helper()
+	g.once.Do(g.verify)
+}
+
+func (g *GoldenFile) verify() {
+	g.t.Helper()
+
+	goldenMu.Lock()
+	delete(goldenFiles, g.id)
+	goldenMu.Unlock()
+
+	got, path := g.Bytes(), g.Path()
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			g.t.Errorf("update golden file: %v", err)
+			return
+		}
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			g.t.Errorf("update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		g.t.Errorf("read golden file: %v, run with -tlogtest.update to create it", err)
+		return
+	}
+	if !bytes.Equal(got, want) {
+		g.t.Errorf("output does not match %s, run with -tlogtest.update to update it\n%s", path, diffLines(string(got), string(want)))
+	}
+}
+
+// diffLines 返回第一处不同的行
+func diffLines(got, want string) string {
+	glines, wlines := strings.Split(got, "\n"), strings.Split(want, "\n")
+	for i := 0; i < len(glines) || i < len(wlines); i++ {
+		var g, w string
+		if i < len(glines) {
+			g = glines[i]
+		}
+		if i < len(wlines) {
+			w = wlines[i]
+		}
+		if g != w {
+			return fmt.Sprintf("line %d:\n\tgot:  %q\n\twant: %q", i+1, g, w)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package tlogtest
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGolden(t *testing.T) {
+	for _, encoding := range []string{"json", "console"} {
+		t.Run(encoding, func(t *testing.T) {
+			g := Golden(t, "golden_"+encoding)
+			logger := g.Logger(encoding)
+			defer logger.Close()
+
+			logger.Debugw("debug", "k", 1, "d", time.Second)
+			logger.Named("access").WithArgs("request", "r1").Infof("info %s", "message")
+			logger.Errorw("error", "error", errors.New("oops"))
+		})
+	}
+}
+
+func TestGoldenVerify(t *testing.T) {
+	if updating() {
+		t.Skip("golden files are being updated")
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		err    string
+	}{
+		{name: "verify", output: "hello\nworld\n"},
+		{name: "verify", output: "hello\ngolden\n", err: "line 2:"},
+		{name: "verify", output: "hello\n", err: "line 2:"},
+		{name: "missing", output: "hello\n", err: "run with -tlogtest.update to create it"},
+	}
+	for i, tc := range tests {
+		tt := &tTestingT{}
+		g := Golden(tt, tc.name)
+		g.Write([]byte(tc.output))
+		g.Verify()
+		g.Verify()
+
+		if tc.err == "" {
+			if tt.failed {
+				t.Errorf("%d: unexpected errors %q", i, tt.errs)
+			}
+			continue
+		}
+		if got, want := len(tt.errs), 1; got != want {
+			t.Fatalf("%d: errors: got %d, want %d", i, got, want)
+		}
+		if !strings.Contains(tt.errs[0], tc.err) {
+			t.Errorf("%d: error %q does not contain %q", i, tt.errs[0], tc.err)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		got, want string
+		diff      string
+	}{
+		{got: "a\nb", want: "a\nb", diff: ""},
+		{got: "a\nb", want: "a\nc", diff: "line 2:\n\tgot:  \"b\"\n\twant: \"c\""},
+		{got: "a", want: "a\nc", diff: "line 2:\n\tgot:  \"\"\n\twant: \"c\""},
+	}
+	for i, tt := range tests {
+		if got, want := diffLines(tt.got, tt.want), tt.diff; got != want {
+			t.Errorf("%d: diff: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+// 导入 tlogtest 的包可以定义自己的 -update 参数
+var update = flag.Bool("update", false, "update test outputs")
+
+func TestGoldenUpdateFlag(t *testing.T) {
+	if flag.Lookup("tlogtest.update") == nil {
+		t.Errorf("flag tlogtest.update is not defined")
+	}
+	if *update {
+		t.Logf("update flag is set")
+	}
+}
@@ -0,0 +1,163 @@
+// Package tlogtest 提供测试用的日志对象, 输出的日志记录在内存中, 可按级别, 消息, 字段及名称查询
+package tlogtest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ironzhang/tlog"
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog"
+	"github.com/ironzhang/tlog/zaplog/zbase"
+)
+
+// TestingT 是 *testing.T 及 *testing.B 实现的接口
+type TestingT interface {
+	zaptest.TestingT
+	Helper()
+}
+
+type Option func(*Logger)
+
+// SetLevel 设置记录的最低日志级别, 默认为 DEBUG
+func SetLevel(level iface.Level) Option {
+	return func(p *Logger) {
+		p.level = level
+	}
+}
+
+// SetTestingLog 将日志同时输出到 t.Log, 使日志与测试输出交织在一起
+func SetTestingLog() Option {
+	return func(p *Logger) {
+		p.testingLog = true
+	}
+}
+
+// SetExitFunc 设置输出 FATAL 级别日志后调用的退出函数, 默认不退出, Fatal 等方法记录日志后返回
+func SetExitFunc(exit func(code int)) Option {
+	return func(p *Logger) {
+		p.exit = exit
+	}
+}
+
+// SetGlobal 通过 tlog.SetLogger 将日志对象设置为全局日志对象,
+// 测试结束时(t.Cleanup)或调用 Restore 时恢复之前的全局日志对象
+func SetGlobal() Option {
+	return func(p *Logger) {
+		p.global = true
+	}
+}
+
+// Logger 记录输出的日志, Named, WithArgs 等返回的日志对象输出的日志同样会被记录
+type Logger struct {
+	*zaplog.Logger
+	t    TestingT
+	logs *observer.ObservedLogs
+
+	level      iface.Level
+	exit       func(code int)
+	testingLog bool
+	global     bool
+	once       sync.Once
+	restore    func()
+}
+
+func New(t TestingT, opts ...Option) *Logger {
+	p := &Logger{t: t, level: iface.DEBUG, exit: func(code int) {}}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	var core zapcore.Core
+	core, p.logs = observer.New(zbase.ZapLevel(p.level))
+	if p.testingLog {
+		tcore := zaptest.NewLogger(t, zaptest.Level(zbase.ZapLevel(p.level))).Core()
+		core = zapcore.NewTee(core, tcore)
+	}
+	p.Logger = zaplog.NewWithCore(core, zaplog.SetExitFunc(p.exit))
+
+	if p.global {
+		p.restore = tlog.ReplaceLogger(p)
+		if c, ok := t.(interface{ Cleanup(func()) }); ok {
+			c.Cleanup(p.Restore)
+		}
+	}
+	return p
+}
+
+// Restore 恢复 SetGlobal 之前的全局日志对象, 多次调用只恢复一次
+func (p *Logger) Restore() {
+	if !p.global {
+		return
+	}
+	p.once.Do(p.restore)
+}
+
+// Entries 返回已记录的全部日志
+func (p *Logger) Entries() Entries {
+	return newEntries(p.logs.All())
+}
+
+// TakeAll 返回并清空已记录的日志
+func (p *Logger) TakeAll() Entries {
+	return newEntries(p.logs.TakeAll())
+}
+
+func newEntries(logs []observer.LoggedEntry) Entries {
+	entries := make(Entries, 0, len(logs))
+	for _, e := range logs {
+		entries = append(entries, newEntry(e))
+	}
+	return entries
+}
+
+// RequireLogged 断言记录了级别, 消息及 kvs 给出的字段都匹配的日志, 否则终止测试
+func (p *Logger) RequireLogged(level iface.Level, message string, kvs ...interface{}) Entry {
+	p.t.Helper()
+	entries := p.Entries()
+	for _, e := range entries {
+		if e.Match(level, message, kvs...) {
+			return e
+		}
+	}
+	p.t.Errorf("no log matched %s", describe(level, message, kvs))
+	p.dump(entries)
+	p.t.FailNow()
+	return Entry{}
+}
+
+// RequireNotLogged 断言没有记录级别, 消息及 kvs 给出的字段都匹配的日志, 否则终止测试
+func (p *Logger) RequireNotLogged(level iface.Level, message string, kvs ...interface{}) {
+	p.t.Helper()
+	for _, e := range p.Entries() {
+		if e.Match(level, message, kvs...) {
+			p.t.Errorf("unexpected log matched %s: %s", describe(level, message, kvs), e)
+			p.t.FailNow()
+			return
+		}
+	}
+}
+
+func (p *Logger) dump(entries Entries) {
+	if len(entries) <= 0 {
+		p.t.Logf("no logs were recorded")
+		return
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "\n\t%s", e)
+	}
+	p.t.Logf("recorded logs:%s", b.String())
+}
+
+func describe(level iface.Level, message string, kvs []interface{}) string {
+	if len(kvs) <= 0 {
+		return fmt.Sprintf("[%s %q]", level, message)
+	}
+	return fmt.Sprintf("[%s %q %v]", level, message, kvs)
+}
@@ -0,0 +1,221 @@
+package tlogtest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ironzhang/tlog"
+	"github.com/ironzhang/tlog/iface"
+)
+
+type tTestingT struct {
+	logs   []string
+	errs   []string
+	failed bool
+}
+
+func (t *tTestingT) Logf(format string, args ...interface{}) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func (t *tTestingT) Errorf(format string, args ...interface{}) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+	t.failed = true
+}
+
+func (t *tTestingT) Fail()        { t.failed = true }
+func (t *tTestingT) Failed() bool { return t.failed }
+func (t *tTestingT) Name() string { return "tTestingT" }
+func (t *tTestingT) FailNow()     { t.failed = true }
+func (t *tTestingT) Helper()      {}
+
+func TestLoggerEntries(t *testing.T) {
+	logger := New(t)
+	logger.Debugw("debug", "k", 1)
+	logger.Infow("info", "k", 2, "err", errors.New("oops"))
+	logger.Named("access").WithArgs("request", "r1").Warnf("warn %d", 3)
+	logger.Errorw("request failed", "request", "r2")
+
+	entries := logger.Entries()
+	if got, want := entries.Len(), 4; got != want {
+		t.Fatalf("entries: got %d, want %d", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		entries Entries
+		want    []string
+	}{
+		{name: "FilterLevel", entries: entries.FilterLevel(iface.INFO), want: []string{"info"}},
+		{name: "FilterMinLevel", entries: entries.FilterMinLevel(iface.WARN), want: []string{"warn 3", "request failed"}},
+		{name: "FilterMessage", entries: entries.FilterMessage("debug"), want: []string{"debug"}},
+		{name: "FilterMessageSnippet", entries: entries.FilterMessageSnippet("request"), want: []string{"request failed"}},
+		{name: "FilterField", entries: entries.FilterField("k", 2), want: []string{"info"}},
+		{name: "FilterFieldError", entries: entries.FilterField("err", errors.New("oops")), want: []string{"info"}},
+		{name: "FilterFieldKey", entries: entries.FilterFieldKey("request"), want: []string{"warn 3", "request failed"}},
+		{name: "FilterName", entries: entries.FilterName("access"), want: []string{"warn 3"}},
+		{name: "Chain", entries: entries.FilterFieldKey("request").FilterLevel(iface.ERROR), want: []string{"request failed"}},
+		{name: "None", entries: entries.FilterName("none"), want: []string{}},
+	}
+	for _, tt := range tests {
+		if got, want := tt.entries.Messages(), tt.want; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: messages: got %q, want %q", tt.name, got, want)
+		}
+	}
+
+	if got, want := entries[0].Caller, "tlogtest/tlogtest_test.go:"; !strings.HasPrefix(got, want) {
+		t.Errorf("caller: got %q, want prefix %q", got, want)
+	}
+}
+
+func TestLoggerTakeAll(t *testing.T) {
+	logger := New(t)
+	logger.Info("hello")
+	if got, want := logger.TakeAll().Len(), 1; got != want {
+		t.Errorf("take all: got %d, want %d", got, want)
+	}
+	if got, want := logger.Entries().Len(), 0; got != want {
+		t.Errorf("entries: got %d, want %d", got, want)
+	}
+}
+
+func TestLoggerLazyValue(t *testing.T) {
+	logger := New(t)
+	logger.WithArgs("lazy", tlog.Lazy(func() interface{} { return "resolved" })).Infow("hello", "k", tlog.Lazy(func() interface{} { return 1 }))
+
+	e := logger.RequireLogged(iface.INFO, "hello", "lazy", "resolved", "k", 1)
+	if got, want := e.Fields["lazy"], "resolved"; got != want {
+		t.Errorf("lazy: got %v, want %v", got, want)
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	logger := New(t, SetLevel(iface.WARN))
+	logger.Info("info")
+	logger.Warn("warn")
+	if got, want := logger.Entries().Messages(), []string{"warn"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("messages: got %q, want %q", got, want)
+	}
+	if logger.Enabled(iface.INFO) {
+		t.Errorf("enabled: INFO should be disabled")
+	}
+}
+
+func TestSetTestingLog(t *testing.T) {
+	tt := &tTestingT{}
+	logger := New(tt, SetTestingLog())
+	logger.Infow("hello", "k", 1)
+	if got, want := len(tt.logs), 1; got != want {
+		t.Fatalf("logs: got %d, want %d", got, want)
+	}
+	if !strings.Contains(tt.logs[0], "hello") {
+		t.Errorf("log: %q does not contain %q", tt.logs[0], "hello")
+	}
+
+	tt = &tTestingT{}
+	New(tt).Infow("hello")
+	if got, want := len(tt.logs), 0; got != want {
+		t.Errorf("logs: got %d, want %d", got, want)
+	}
+}
+
+func TestRequireLogged(t *testing.T) {
+	tests := []struct {
+		message string
+		kvs     []interface{}
+		failed  bool
+	}{
+		{message: "hello", failed: false},
+		{message: "hello", kvs: []interface{}{"k", 1}, failed: false},
+		{message: "hello", kvs: []interface{}{"k", "1"}, failed: false},
+		{message: "hello", kvs: []interface{}{"k", 2}, failed: true},
+		{message: "hello", kvs: []interface{}{"x", 1}, failed: true},
+		{message: "world", failed: true},
+	}
+	for i, tc := range tests {
+		tt := &tTestingT{}
+		logger := New(tt)
+		logger.Infow("hello", "k", 1)
+		logger.RequireLogged(iface.INFO, tc.message, tc.kvs...)
+		if got, want := tt.failed, tc.failed; got != want {
+			t.Errorf("%d: failed: got %v, want %v", i, got, want)
+		}
+		if tc.failed && len(tt.logs) != 1 {
+			t.Errorf("%d: recorded logs were not dumped", i)
+		}
+	}
+
+	tt := &tTestingT{}
+	New(tt).RequireLogged(iface.INFO, "hello")
+	if got, want := tt.logs, []string{"no logs were recorded"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("logs: got %q, want %q", got, want)
+	}
+}
+
+func TestRequireNotLogged(t *testing.T) {
+	tests := []struct {
+		level   iface.Level
+		message string
+		kvs     []interface{}
+		failed  bool
+	}{
+		{level: iface.INFO, message: "hello", failed: true},
+		{level: iface.INFO, message: "hello", kvs: []interface{}{"k", 1}, failed: true},
+		{level: iface.WARN, message: "hello", failed: false},
+		{level: iface.INFO, message: "hello", kvs: []interface{}{"k", 2}, failed: false},
+		{level: iface.INFO, message: "world", failed: false},
+	}
+	for i, tc := range tests {
+		tt := &tTestingT{}
+		logger := New(tt)
+		logger.Infow("hello", "k", 1)
+		logger.RequireNotLogged(tc.level, tc.message, tc.kvs...)
+		if got, want := tt.failed, tc.failed; got != want {
+			t.Errorf("%d: failed: got %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestSetGlobal(t *testing.T) {
+	old := tlog.GetLogger()
+
+	var logger *Logger
+	t.Run("Global", func(t *testing.T) {
+		logger = New(t, SetGlobal())
+		if tlog.GetLogger() != logger {
+			t.Fatalf("global logger was not replaced")
+		}
+		tlog.Infow("hello", "k", 1)
+		logger.RequireLogged(iface.INFO, "hello", "k", 1)
+	})
+	if tlog.GetLogger() != old {
+		t.Errorf("global logger was not restored")
+	}
+
+	tt := &tTestingT{}
+	logger = New(tt, SetGlobal())
+	if tlog.GetLogger() != logger {
+		t.Fatalf("global logger was not replaced")
+	}
+	logger.Restore()
+	logger.Restore()
+	if tlog.GetLogger() != old {
+		t.Errorf("global logger was not restored")
+	}
+}
+
+func TestLoggerFatal(t *testing.T) {
+	logger := New(t)
+	logger.Fatalw("fatal", "k", 1)
+	logger.RequireLogged(iface.FATAL, "fatal", "k", 1)
+
+	var codes []int
+	logger = New(t, SetExitFunc(func(code int) { codes = append(codes, code) }))
+	logger.Fatal("fatal")
+	if got, want := codes, []int{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("codes: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+package tlog
+
+import (
+	"fmt"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+// Valuer 由需要延迟计算的日志字段值实现, 只在日志确实输出时才调用 LogValue, 计算结果按其类型编码,
+// 通过 WithArgs 绑定时每次输出日志都会重新计算
+type Valuer = iface.Valuer
+
+type lazyValuer func() interface{}
+
+func (f lazyValuer) LogValue() interface{} {
+	return f()
+}
+
+// Lazy 返回在日志确实输出时才调用 f 计算的字段值
+func Lazy(f func() interface{}) Valuer {
+	return lazyValuer(f)
+}
+
+type stringerValuer struct {
+	s fmt.Stringer
+}
+
+func (v stringerValuer) LogValue() interface{} {
+	return v.s.String()
+}
+
+// Stringer 返回在日志确实输出时才调用 s.String 的字段值
+func Stringer(s fmt.Stringer) Valuer {
+	return stringerValuer{s: s}
+}
@@ -0,0 +1,43 @@
+package tlog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ironzhang/tlog"
+	"github.com/ironzhang/tlog/iface"
+)
+
+func TestLazy(t *testing.T) {
+	count := 0
+	v := tlog.Lazy(func() interface{} {
+		count++
+		return count
+	})
+	if count != 0 {
+		t.Fatalf("lazy: resolved before LogValue")
+	}
+	if got, want := v.LogValue(), 1; got != want {
+		t.Errorf("lazy: got %v, want %v", got, want)
+	}
+}
+
+func TestStringer(t *testing.T) {
+	v := tlog.Stringer(time.Second)
+	if got, want := v.LogValue(), "1s"; got != want {
+		t.Errorf("stringer: got %v, want %v", got, want)
+	}
+}
+
+func TestValuerLog(t *testing.T) {
+	logger, logs := newObservedLogger()
+	logger.Debugw("lazy", "dump", tlog.Lazy(func() interface{} { return "value" }))
+
+	entries := logs.AllUntimed()
+	if got, want := len(entries), 1; got != want {
+		t.Fatalf("entries: got %d, want %d", got, want)
+	}
+	if got, want := iface.Resolve(entries[0].ContextMap()["dump"]), "value"; got != want {
+		t.Errorf("dump: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,26 @@
+package zaplog
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// clockCore 使用 now 返回的时间作为日志条目的时间
+type clockCore struct {
+	zapcore.Core
+	now func() time.Time
+}
+
+func newClockCore(core zapcore.Core, now func() time.Time) zapcore.Core {
+	return &clockCore{Core: core, now: now}
+}
+
+func (c *clockCore) With(fields []zapcore.Field) zapcore.Core {
+	return &clockCore{Core: c.Core.With(fields), now: c.now}
+}
+
+func (c *clockCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	ent.Time = c.now()
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,65 @@
+package zaplog
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zsink"
+)
+
+func TestClockCore(t *testing.T) {
+	esink := &tEntrySink{}
+	err := zsink.RegisterSink("TestClockCore", func(u *url.URL) (zap.Sink, error) {
+		return esink, nil
+	})
+	if err != nil {
+		t.Fatalf("register sink: %v", err)
+	}
+
+	encoder := NewConsoleEncoderConfig()
+	encoder.EncodeCaller = BaseCallerEncoder
+	cfg := Config{
+		Level: iface.DEBUG,
+		Cores: []CoreConfig{
+			{
+				Name:     "Test",
+				Encoding: "console",
+				Encoder:  encoder,
+				MinLevel: iface.DEBUG,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{"TestClockCore://1"},
+			},
+		},
+		Loggers: []LoggerConfig{
+			{
+				Cores: []string{"Test"},
+			},
+		},
+	}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	logger, err := New(cfg, SetClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("hello")
+	logger.WithArgs("k", 1).Named("access").Info("world")
+
+	if got, want := len(esink.data), 2; got != want {
+		t.Fatalf("data: got %d, want %d", got, want)
+	}
+	for i, prefix := range []string{
+		"2020-01-01T00:00:00.000Z\tINFO\tclock_test.go:",
+		"2020-01-01T00:00:00.000Z\tINFO\taccess\tclock_test.go:",
+	} {
+		if !strings.HasPrefix(esink.data[i], prefix) {
+			t.Errorf("%d: data: got %q, want prefix %q", i, esink.data[i], prefix)
+		}
+	}
+}
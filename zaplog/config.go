@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strconv"
 
 	"go.uber.org/zap/zapcore"
 
@@ -17,6 +19,8 @@ var (
 	errUnmarshalNilDurationEncoder = errors.New("can't unmarshal a nil *DurationEncoder")
 	errUnmarshalNilCallerEncoder   = errors.New("can't unmarshal a nil *CallerEncoder")
 	errUnmarshalNilNameEncoder     = errors.New("can't unmarshal a nil *NameEncoder")
+	errUnmarshalNilOutputMode      = errors.New("can't unmarshal a nil *OutputMode")
+	errUnmarshalNilMaskStrategy    = errors.New("can't unmarshal a nil *MaskStrategy")
 )
 
 type StacktraceLevel int8
@@ -292,6 +296,7 @@ type CallerEncoder int8
 const (
 	ShortCallerEncoder CallerEncoder = iota
 	FullCallerEncoder
+	BaseCallerEncoder // 只输出文件名及行号, 不受构建路径影响, 用于 golden 文件等需要稳定输出的场景
 )
 
 func (e CallerEncoder) zap() zapcore.CallerEncoder {
@@ -300,6 +305,8 @@ func (e CallerEncoder) zap() zapcore.CallerEncoder {
 		return zapcore.ShortCallerEncoder
 	case FullCallerEncoder:
 		return zapcore.FullCallerEncoder
+	case BaseCallerEncoder:
+		return baseCallerEncoder
 	default:
 		return zapcore.ShortCallerEncoder
 	}
@@ -311,6 +318,8 @@ func (e CallerEncoder) String() string {
 		return "short"
 	case FullCallerEncoder:
 		return "full"
+	case BaseCallerEncoder:
+		return "base"
 	default:
 		return fmt.Sprintf("CallerEncoder(%d)", e)
 	}
@@ -336,12 +345,22 @@ func (e *CallerEncoder) unmarshalText(text []byte) bool {
 		*e = ShortCallerEncoder
 	case "full", "FULL":
 		*e = FullCallerEncoder
+	case "base", "BASE":
+		*e = BaseCallerEncoder
 	default:
 		return false
 	}
 	return true
 }
 
+func baseCallerEncoder(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+	if !caller.Defined {
+		enc.AppendString("undefined")
+		return
+	}
+	enc.AppendString(filepath.Base(caller.File) + ":" + strconv.Itoa(caller.Line))
+}
+
 type NameEncoder int8
 
 const (
@@ -390,6 +409,105 @@ func (e *NameEncoder) unmarshalText(text []byte) bool {
 	return true
 }
 
+type OutputMode int8
+
+const (
+	FanoutMode OutputMode = iota
+	FailoverMode
+	RoundRobinMode
+)
+
+func (m OutputMode) String() string {
+	switch m {
+	case FanoutMode:
+		return "fanout"
+	case FailoverMode:
+		return "failover"
+	case RoundRobinMode:
+		return "roundrobin"
+	default:
+		return fmt.Sprintf("OutputMode(%d)", m)
+	}
+}
+
+func (m OutputMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *OutputMode) UnmarshalText(text []byte) error {
+	if m == nil {
+		return errUnmarshalNilOutputMode
+	}
+	if !m.unmarshalText(text) && !m.unmarshalText(bytes.ToLower(text)) {
+		return fmt.Errorf("unrecognized output mode %q", text)
+	}
+	return nil
+}
+
+func (m *OutputMode) unmarshalText(text []byte) bool {
+	switch string(text) {
+	case "fanout", "FANOUT", "":
+		*m = FanoutMode
+	case "failover", "FAILOVER":
+		*m = FailoverMode
+	case "roundrobin", "ROUNDROBIN":
+		*m = RoundRobinMode
+	default:
+		return false
+	}
+	return true
+}
+
+// MaskStrategy 脱敏方式
+type MaskStrategy int8
+
+const (
+	FullMask    MaskStrategy = iota // 整体替换为固定的掩码
+	PartialMask                     // 保留首尾部分字符
+	HashMask                        // 替换为 sha256 摘要的前缀, 可用于关联但不可还原
+)
+
+func (m MaskStrategy) String() string {
+	switch m {
+	case FullMask:
+		return "full"
+	case PartialMask:
+		return "partial"
+	case HashMask:
+		return "hash"
+	default:
+		return fmt.Sprintf("MaskStrategy(%d)", m)
+	}
+}
+
+func (m MaskStrategy) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *MaskStrategy) UnmarshalText(text []byte) error {
+	if m == nil {
+		return errUnmarshalNilMaskStrategy
+	}
+	if !m.unmarshalText(text) && !m.unmarshalText(bytes.ToLower(text)) {
+		return fmt.Errorf("unrecognized mask strategy %q", text)
+	}
+	return nil
+}
+
+func (m *MaskStrategy) unmarshalText(text []byte) bool {
+	switch string(text) {
+	case "full", "FULL", "":
+		*m = FullMask
+	case "partial", "PARTIAL":
+		*m = PartialMask
+	case "hash", "HASH":
+		*m = HashMask
+	default:
+		return false
+	}
+	return true
+}
+
 type EncoderConfig struct {
 	MessageKey     string          `json:"messageKey,omitempty" yaml:"messageKey,omitempty"`
 	LevelKey       string          `json:"levelKey,omitempty" yaml:"levelKey,omitempty"`
@@ -404,20 +522,45 @@ type EncoderConfig struct {
 	EncodeName     NameEncoder     `json:"nameEncoder,omitempty" yaml:"nameEncoder,omitempty"`
 }
 
-type CoreConfig struct {
-	Name     string        `json:"name" yaml:"name"`
-	Encoding string        `json:"encoding,omitempty" yaml:"encoding,omitempty"`
-	Encoder  EncoderConfig `json:"encoder,omitempty" yaml:"encoder,omitempty"`
-	MinLevel iface.Level   `json:"minLevel" yaml:"minLevel"`
-	MaxLevel iface.Level   `json:"maxLevel" yaml:"maxLevel"`
-	URLs     []string      `json:"urls,omitempty" yaml:"urls,omitempty"`
+// OutputConfig 描述一个输出, URL 不为空时为单个输出, 否则按 Mode 组合 Outputs;
+// Weight 为 RoundRobinMode 下的权重, 默认为 1
+type OutputConfig struct {
+	Mode    OutputMode     `json:"mode,omitempty" yaml:"mode,omitempty"`
+	URL     string         `json:"url,omitempty" yaml:"url,omitempty"`
+	Weight  int            `json:"weight,omitempty" yaml:"weight,omitempty"`
+	Outputs []OutputConfig `json:"outputs,omitempty" yaml:"outputs,omitempty"`
 }
 
+// RedactRule 描述一条脱敏规则, Keys 按字段名匹配, 不区分大小写, 也匹配分组字段名的最后一段;
+// Pattern 为正则表达式, 匹配字符串字段的值及日志消息中的内容, 只替换匹配的部分
+type RedactRule struct {
+	Keys    []string     `json:"keys,omitempty" yaml:"keys,omitempty"`
+	Pattern string       `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Mask    MaskStrategy `json:"mask,omitempty" yaml:"mask,omitempty"`
+}
+
+// CoreConfig 的 URLs 及 Outputs 中的输出都会被写入;
+// URLs 中可以使用 failover(url1, url2), roundrobin(url1, url2), fanout(url1, url2) 组合输出;
+// Redact 只作用于写入该 core 的日志, 其他 core 不受影响
+type CoreConfig struct {
+	Name     string         `json:"name" yaml:"name"`
+	Encoding string         `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	Encoder  EncoderConfig  `json:"encoder,omitempty" yaml:"encoder,omitempty"`
+	MinLevel iface.Level    `json:"minLevel" yaml:"minLevel"`
+	MaxLevel iface.Level    `json:"maxLevel" yaml:"maxLevel"`
+	URLs     []string       `json:"urls,omitempty" yaml:"urls,omitempty"`
+	Outputs  []OutputConfig `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Redact   []RedactRule   `json:"redact,omitempty" yaml:"redact,omitempty"`
+}
+
+// LoggerConfig 的 Redact 作用于该日志对象输出的所有日志, 在写入各个 core
+// 及 ContextHook 添加的 core (如 span 事件) 之前脱敏, 各个 core 再按其自身的 Redact 脱敏
 type LoggerConfig struct {
 	Name            string          `json:"name,omitempty" yaml:"name,omitempty"`
 	DisableCaller   bool            `json:"disableCaller,omitempty" yaml:"disableCaller,omitempty"`
 	StacktraceLevel StacktraceLevel `json:"stacktraceLevel,omitempty" yaml:"stacktraceLevel,omitempty"`
 	Cores           []string        `json:"cores,omitempty" yaml:"cores,omitempty"`
+	Redact          []RedactRule    `json:"redact,omitempty" yaml:"redact,omitempty"`
 }
 
 type Config struct {
@@ -274,6 +274,7 @@ func TestCallerEncoderMarshal(t *testing.T) {
 		{e: -1, s: "CallerEncoder(-1)"},
 		{e: ShortCallerEncoder, s: "short"},
 		{e: FullCallerEncoder, s: "full"},
+		{e: BaseCallerEncoder, s: "base"},
 	}
 	for i, tt := range tests {
 		text, err := tt.e.MarshalText()
@@ -301,6 +302,8 @@ func TestCallerEncoderUnmarshal(t *testing.T) {
 		{s: "full", e: FullCallerEncoder},
 		{s: "FULL", e: FullCallerEncoder},
 		{s: "fULL", e: FullCallerEncoder},
+		{s: "base", e: BaseCallerEncoder},
+		{s: "BASE", e: BaseCallerEncoder},
 	}
 	for i, tt := range tests {
 		var e CallerEncoder
@@ -374,6 +377,117 @@ func TestNameEncoderUnmarshal(t *testing.T) {
 	}
 }
 
+func TestOutputModeMarshal(t *testing.T) {
+	tests := []struct {
+		m OutputMode
+		s string
+	}{
+		{m: -1, s: "OutputMode(-1)"},
+		{m: FanoutMode, s: "fanout"},
+		{m: FailoverMode, s: "failover"},
+		{m: RoundRobinMode, s: "roundrobin"},
+	}
+	for i, tt := range tests {
+		text, err := tt.m.MarshalText()
+		if err != nil {
+			t.Errorf("%d: marshal text: %v", i, err)
+			continue
+		}
+		if got, want := string(text), tt.s; got != want {
+			t.Errorf("%d: text: got %v, want %v", i, got, want)
+			continue
+		}
+		t.Logf("%d: text: got %s", i, text)
+	}
+}
+
+func TestOutputModeUnmarshal(t *testing.T) {
+	tests := []struct {
+		s   string
+		m   OutputMode
+		err string
+	}{
+		{s: "OutputMode(-1)", err: "unrecognized output mode"},
+		{s: "", m: FanoutMode},
+		{s: "fanout", m: FanoutMode},
+		{s: "Failover", m: FailoverMode},
+		{s: "roundRobin", m: RoundRobinMode},
+		{s: "ROUNDROBIN", m: RoundRobinMode},
+	}
+	for i, tt := range tests {
+		var m OutputMode
+		err := m.UnmarshalText([]byte(tt.s))
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: got %v, want %v", i, err, tt.err)
+			continue
+		}
+		if err != nil {
+			t.Logf("%d: unmarshal text: %v", i, err)
+			continue
+		}
+		if got, want := m, tt.m; got != want {
+			t.Errorf("%d: output mode: got %v, want %v", i, got, want)
+			continue
+		}
+		t.Logf("%d: output mode: got %v", i, m)
+	}
+}
+
+func TestMaskStrategyMarshal(t *testing.T) {
+	tests := []struct {
+		m MaskStrategy
+		s string
+	}{
+		{m: -1, s: "MaskStrategy(-1)"},
+		{m: FullMask, s: "full"},
+		{m: PartialMask, s: "partial"},
+		{m: HashMask, s: "hash"},
+	}
+	for i, tt := range tests {
+		text, err := tt.m.MarshalText()
+		if err != nil {
+			t.Errorf("%d: marshal text: %v", i, err)
+			continue
+		}
+		if got, want := string(text), tt.s; got != want {
+			t.Errorf("%d: text: got %v, want %v", i, got, want)
+			continue
+		}
+		t.Logf("%d: text: got %s", i, text)
+	}
+}
+
+func TestMaskStrategyUnmarshal(t *testing.T) {
+	tests := []struct {
+		s   string
+		m   MaskStrategy
+		err string
+	}{
+		{s: "MaskStrategy(-1)", err: "unrecognized mask strategy"},
+		{s: "", m: FullMask},
+		{s: "full", m: FullMask},
+		{s: "Partial", m: PartialMask},
+		{s: "HASH", m: HashMask},
+	}
+	for i, tt := range tests {
+		var m MaskStrategy
+		err := m.UnmarshalText([]byte(tt.s))
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: got %v, want %v", i, err, tt.err)
+			continue
+		}
+		if err != nil {
+			t.Logf("%d: unmarshal text: %v", i, err)
+			continue
+		}
+		if got, want := m, tt.m; got != want {
+			t.Errorf("%d: mask strategy: got %v, want %v", i, got, want)
+			continue
+		}
+		t.Logf("%d: mask strategy: got %v", i, m)
+	}
+}
+
 type tPrimitiveArrayEncoder struct {
 	elems []interface{}
 }
@@ -467,12 +581,17 @@ func TestCallerEncoder(t *testing.T) {
 		{encoder: -1, elems: []interface{}{caller.TrimmedPath()}},
 		{encoder: ShortCallerEncoder, elems: []interface{}{caller.TrimmedPath()}},
 		{encoder: FullCallerEncoder, elems: []interface{}{caller.String()}},
+		{encoder: BaseCallerEncoder, elems: []interface{}{"config_test.go:460"}},
 	}
 	for i, tt := range tests {
 		enc := &tPrimitiveArrayEncoder{}
 		tt.encoder.zap()(caller, enc)
 		assert.Equal(t, tt.elems, enc.elems, "%d: unexpected caller encoder elements", i)
 	}
+
+	enc := &tPrimitiveArrayEncoder{}
+	BaseCallerEncoder.zap()(zapcore.EntryCaller{}, enc)
+	assert.Equal(t, []interface{}{"undefined"}, enc.elems, "unexpected undefined caller elements")
 }
 
 func TestNameEncoder(t *testing.T) {
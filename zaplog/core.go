@@ -0,0 +1,73 @@
+package zaplog
+
+import (
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/zaplog/zsink"
+)
+
+// newEntryCore 创建一个将日志条目连同编码结果一起传给 zsink.EntrySink 的 core,
+// 除此之外与 zapcore.NewCore 创建的 core 行为一致
+func newEntryCore(enc zapcore.Encoder, out zsink.EntrySink, enab zapcore.LevelEnabler) zapcore.Core {
+	return &entryCore{
+		LevelEnabler: enab,
+		enc:          enc,
+		out:          out,
+	}
+}
+
+type entryCore struct {
+	zapcore.LevelEnabler
+	enc    zapcore.Encoder
+	out    zsink.EntrySink
+	fields []zapcore.Field
+}
+
+func (c *entryCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &entryCore{
+		LevelEnabler: c.LevelEnabler,
+		enc:          c.enc.Clone(),
+		out:          c.out,
+		fields:       make([]zapcore.Field, 0, len(c.fields)+len(fields)),
+	}
+	clone.fields = append(clone.fields, c.fields...)
+	clone.fields = append(clone.fields, fields...)
+	for i := range fields {
+		fields[i].AddTo(clone.enc)
+	}
+	return clone
+}
+
+func (c *entryCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *entryCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	all := fields
+	if len(c.fields) > 0 {
+		all = make([]zapcore.Field, 0, len(c.fields)+len(fields))
+		all = append(all, c.fields...)
+		all = append(all, fields...)
+	}
+	_, err = c.out.WriteEntry(ent, all, buf.Bytes())
+	buf.Free()
+	if err != nil {
+		return err
+	}
+	if ent.Level > zapcore.ErrorLevel {
+		// 与 zapcore 一致, 在 panic 或退出前同步输出
+		c.Sync()
+	}
+	return nil
+}
+
+func (c *entryCore) Sync() error {
+	return c.out.Sync()
+}
@@ -0,0 +1,87 @@
+package zaplog
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zsink"
+)
+
+type tEntrySink struct {
+	tStatSink
+	levels []zapcore.Level
+	fields [][]string
+	data   []string
+}
+
+func (p *tEntrySink) WriteEntry(ent zapcore.Entry, fields []zapcore.Field, b []byte) (int, error) {
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		keys = append(keys, f.Key)
+	}
+	p.levels = append(p.levels, ent.Level)
+	p.fields = append(p.fields, keys)
+	p.data = append(p.data, string(b))
+	return len(b), nil
+}
+
+func TestEntryCore(t *testing.T) {
+	esink := &tEntrySink{}
+	err := zsink.RegisterSink("TestEntryCore", func(u *url.URL) (zap.Sink, error) {
+		return esink, nil
+	})
+	if err != nil {
+		t.Fatalf("register sink: %v", err)
+	}
+	tsink := RegisterTestSink(t, "TestEntryCorePlain")
+
+	cfg := Config{
+		Level: iface.DEBUG,
+		Cores: []CoreConfig{
+			{
+				Name:     "Test",
+				Encoding: "json",
+				Encoder:  NewJSONEncoderConfig(),
+				MinLevel: iface.INFO,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{"TestEntryCore://1", "TestEntryCorePlain://1"},
+			},
+		},
+		Loggers: []LoggerConfig{
+			{
+				Cores: []string{"Test"},
+			},
+		},
+	}
+	logger, err := New(cfg)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	defer logger.Close()
+
+	l := logger.WithArgs("request", 1)
+	l.Debug("debug")
+	l.Infow("info", "user", "u1")
+	l.Error("error")
+
+	if got, want := len(esink.levels), 2; got != want {
+		t.Fatalf("entries: got %d, want %d", got, want)
+	}
+	if esink.levels[0] != zapcore.InfoLevel || esink.levels[1] != zapcore.ErrorLevel {
+		t.Errorf("levels: got %v", esink.levels)
+	}
+	if got, want := strings.Join(esink.fields[0], ","), "request,user"; got != want {
+		t.Errorf("fields: got %q, want %q", got, want)
+	}
+	if data := esink.data[0]; !strings.Contains(data, `"request":1`) || !strings.Contains(data, `"user":"u1"`) {
+		t.Errorf("data: got %q", data)
+	}
+	if got, want := tsink.writeCount, 2; got != want {
+		t.Errorf("plain sink writes: got %d, want %d", got, want)
+	}
+}
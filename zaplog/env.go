@@ -0,0 +1,121 @@
+package zaplog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// StdLogger 在首次使用时读取的环境变量
+const (
+	EnvLevel    = "TLOG_LEVEL"    // 日志级别, 如 info
+	EnvConfig   = "TLOG_CONFIG"   // 配置文件路径, .yaml/.yml 按 YAML 解析, 其余按 JSON 解析
+	EnvEncoding = "TLOG_ENCODING" // 所有 core 的编码, json 或 console
+	EnvOutput   = "TLOG_OUTPUT"   // 输出, 多个 URL 以逗号分隔, 如 stderr,rfile://workdir/log/app.log; 设置时所有 core 合并为一个
+)
+
+// LoadConfig 从文件加载配置, 扩展名为 .yaml/.yml 时按 YAML 解析, 否则按 JSON 解析
+func LoadConfig(file string) (cfg Config, err error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return cfg, err
+	}
+
+	unmarshal := json.Unmarshal
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	}
+	if err = unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse %s: %v", file, err)
+	}
+	return cfg, nil
+}
+
+// NewEnvConfig 根据环境变量生成配置: 设置了 TLOG_CONFIG 时从该文件加载, 否则使用 NewDevelopmentConfig;
+// 再以 TLOG_LEVEL, TLOG_ENCODING, TLOG_OUTPUT 覆盖配置中的级别, 编码及输出
+func NewEnvConfig() (Config, error) {
+	return newEnvConfig(os.Getenv)
+}
+
+func newEnvConfig(getenv func(string) string) (cfg Config, err error) {
+	if file := getenv(EnvConfig); file != "" {
+		if cfg, err = LoadConfig(file); err != nil {
+			return cfg, err
+		}
+	} else {
+		cfg = NewDevelopmentConfig()
+	}
+
+	if level := getenv(EnvLevel); level != "" {
+		if err = cfg.Level.UnmarshalText([]byte(level)); err != nil {
+			return cfg, fmt.Errorf("%s: %v", EnvLevel, err)
+		}
+	}
+	if encoding := getenv(EnvEncoding); encoding != "" {
+		if err = setEncoding(&cfg, encoding); err != nil {
+			return cfg, fmt.Errorf("%s: %v", EnvEncoding, err)
+		}
+	}
+	if output := getenv(EnvOutput); output != "" {
+		if err = setOutput(&cfg, output); err != nil {
+			return cfg, fmt.Errorf("%s: %v", EnvOutput, err)
+		}
+	}
+	return cfg, nil
+}
+
+// setEncoding 设置所有 core 的编码, 编码改变时同时使用该编码的默认编码配置
+func setEncoding(cfg *Config, encoding string) error {
+	var encoder EncoderConfig
+	switch encoding {
+	case "json":
+		encoder = NewJSONEncoderConfig()
+	case "console":
+		encoder = NewConsoleEncoderConfig()
+	default:
+		return fmt.Errorf("unknown encoding %q", encoding)
+	}
+	for i := range cfg.Cores {
+		if cfg.Cores[i].Encoding != encoding {
+			cfg.Cores[i].Encoding = encoding
+			cfg.Cores[i].Encoder = encoder
+		}
+	}
+	return nil
+}
+
+// setOutput 将所有 core 合并为一个输出到 output 的 core, 避免多个 core 分别打开同一个输出(如同一个文件);
+// 合并后的 core 使用第一个 core 的名称及编码, 级别范围覆盖所有 core, 并使用所有 core 的脱敏规则
+func setOutput(cfg *Config, output string) error {
+	urls, err := splitOutputArgs(output)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Cores) <= 0 {
+		return errors.New("no cores")
+	}
+
+	core := cfg.Cores[0]
+	core.URLs, core.Outputs = urls, nil
+	for _, c := range cfg.Cores[1:] {
+		if c.MinLevel < core.MinLevel {
+			core.MinLevel = c.MinLevel
+		}
+		if c.MaxLevel > core.MaxLevel {
+			core.MaxLevel = c.MaxLevel
+		}
+		core.Redact = append(core.Redact[:len(core.Redact):len(core.Redact)], c.Redact...)
+	}
+	cfg.Cores = []CoreConfig{core}
+	for i := range cfg.Loggers {
+		cfg.Loggers[i].Cores = []string{core.Name}
+	}
+	return nil
+}
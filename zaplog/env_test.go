@@ -0,0 +1,179 @@
+package zaplog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+const testYAMLConfig = `
+level: warn
+cores:
+  - name: File
+    encoding: json
+    minlevel: debug
+    maxlevel: fatal
+    urls:
+      - stderr
+loggers:
+  - cores:
+      - File
+`
+
+const testJSONConfig = `{"level":"error","cores":[{"name":"File","encoding":"json","minlevel":"debug","maxlevel":"fatal","urls":["stderr"]}],"loggers":[{"cores":["File"]}]}`
+
+func writeTestFile(t *testing.T, dir, name, data string) string {
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	return file
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zaplog")
+	if err != nil {
+		t.Fatalf("temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		file  string
+		level iface.Level
+	}{
+		{file: writeTestFile(t, dir, "config.yaml", testYAMLConfig), level: iface.WARN},
+		{file: writeTestFile(t, dir, "config.yml", testYAMLConfig), level: iface.WARN},
+		{file: writeTestFile(t, dir, "config.json", testJSONConfig), level: iface.ERROR},
+	}
+	for i, tt := range tests {
+		cfg, err := LoadConfig(tt.file)
+		if err != nil {
+			t.Fatalf("%d: load config: %v", i, err)
+		}
+		if got, want := cfg.Level, tt.level; got != want {
+			t.Errorf("%d: level: got %v, want %v", i, got, want)
+		}
+		if got, want := cfg.Cores[0].URLs, []string{"stderr"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%d: urls: got %v, want %v", i, got, want)
+		}
+	}
+
+	if _, err = LoadConfig(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("load missing config: expected error")
+	}
+	if _, err = LoadConfig(writeTestFile(t, dir, "bad.json", "{")); err == nil {
+		t.Errorf("load bad config: expected error")
+	}
+}
+
+func TestNewEnvConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zaplog")
+	if err != nil {
+		t.Fatalf("temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := writeTestFile(t, dir, "config.yaml", testYAMLConfig)
+
+	tests := []struct {
+		env      map[string]string
+		level    iface.Level
+		encoding string
+		urls     []string
+	}{
+		{
+			env:      nil,
+			level:    iface.DEBUG,
+			encoding: "console",
+			urls:     []string{"stdout"},
+		},
+		{
+			env:      map[string]string{EnvLevel: "INFO", EnvEncoding: "json", EnvOutput: "stderr, rfile://workdir/log/app.log"},
+			level:    iface.INFO,
+			encoding: "json",
+			urls:     []string{"stderr", "rfile://workdir/log/app.log"},
+		},
+		{
+			env:      map[string]string{EnvConfig: file},
+			level:    iface.WARN,
+			encoding: "json",
+			urls:     []string{"stderr"},
+		},
+		{
+			env:      map[string]string{EnvConfig: file, EnvLevel: "error", EnvEncoding: "console", EnvOutput: "stdout"},
+			level:    iface.ERROR,
+			encoding: "console",
+			urls:     []string{"stdout"},
+		},
+	}
+	for i, tt := range tests {
+		cfg, err := newEnvConfig(func(key string) string { return tt.env[key] })
+		if err != nil {
+			t.Fatalf("%d: new env config: %v", i, err)
+		}
+		if got, want := cfg.Level, tt.level; got != want {
+			t.Errorf("%d: level: got %v, want %v", i, got, want)
+		}
+		for _, c := range cfg.Cores {
+			if got, want := c.Encoding, tt.encoding; got != want {
+				t.Errorf("%d: %s: encoding: got %q, want %q", i, c.Name, got, want)
+			}
+			if got, want := c.URLs, tt.urls; !reflect.DeepEqual(got, want) {
+				t.Errorf("%d: %s: urls: got %v, want %v", i, c.Name, got, want)
+			}
+		}
+		if _, err = New(cfg); err != nil {
+			t.Errorf("%d: new: %v", i, err)
+		}
+	}
+}
+
+func TestNewEnvConfigError(t *testing.T) {
+	tests := []map[string]string{
+		{EnvConfig: "testdata/missing.yaml"},
+		{EnvLevel: "verbose"},
+		{EnvEncoding: "xml"},
+		{EnvOutput: " , "},
+	}
+	for i, env := range tests {
+		_, err := newEnvConfig(func(key string) string { return env[key] })
+		if err == nil {
+			t.Errorf("%d: new env config: expected error", i)
+			continue
+		}
+		t.Logf("%d: new env config: %v", i, err)
+	}
+}
+
+func TestSetOutput(t *testing.T) {
+	cfg := NewProductionConfig()
+	if err := setOutput(&cfg, "rfile://workdir/log/app.log, kafka://b1:9092,b2:9092/logs"); err != nil {
+		t.Fatalf("set output: %v", err)
+	}
+	if got, want := len(cfg.Cores), 1; got != want {
+		t.Fatalf("cores: got %d, want %d", got, want)
+	}
+	core := cfg.Cores[0]
+	if got, want := core.URLs, []string{"rfile://workdir/log/app.log", "kafka://b1:9092,b2:9092/logs"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("urls: got %v, want %v", got, want)
+	}
+	if core.MinLevel != iface.DEBUG || core.MaxLevel != iface.FATAL {
+		t.Errorf("levels: got [%v, %v], want [%v, %v]", core.MinLevel, core.MaxLevel, iface.DEBUG, iface.FATAL)
+	}
+	for _, logger := range cfg.Loggers {
+		if got, want := logger.Cores, []string{core.Name}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: cores: got %v, want %v", logger.Name, got, want)
+		}
+	}
+
+	// 相对路径的文件不是 URL 的一部分
+	if err := setOutput(&cfg, "stderr,logs/app.log"); err != nil {
+		t.Fatalf("set output: %v", err)
+	}
+	if got, want := cfg.Cores[0].URLs, []string{"stderr", "logs/app.log"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("urls: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package zaplog
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/zaplog/zbase"
+)
+
+// errorCore 展开错误字段: 错误信息输出为 key 字段, 原因链输出为 keyCauses 字段;
+// 错误带有调用栈时, json 编码输出为 keyStack 字段, console 编码则附加在日志之后, 与 stacktrace 一样换行输出
+type errorCore struct {
+	zapcore.Core
+	console bool
+	stacks  []string // console 编码时 With 绑定的错误的调用栈
+}
+
+func newErrorCore(core zapcore.Core, encoding string) zapcore.Core {
+	return &errorCore{Core: core, console: encoding == "console" || encoding == ""}
+}
+
+func (c *errorCore) With(fields []zapcore.Field) zapcore.Core {
+	fields, stacks := c.expand(fields)
+	return &errorCore{
+		Core:    c.Core.With(fields),
+		console: c.console,
+		stacks:  append(c.stacks[:len(c.stacks):len(c.stacks)], stacks...),
+	}
+}
+
+func (c *errorCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *errorCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields, stacks := c.expand(fields)
+	if stacks = append(c.stacks[:len(c.stacks):len(c.stacks)], stacks...); len(stacks) > 0 {
+		if ent.Stack != "" {
+			stacks = append(stacks, ent.Stack)
+		}
+		ent.Stack = strings.Join(stacks, "\n")
+	}
+	return c.Core.Write(ent, fields)
+}
+
+func hasErrorField(fields []zapcore.Field) bool {
+	for _, f := range fields {
+		if f.Type == zapcore.ErrorType {
+			return true
+		}
+	}
+	return false
+}
+
+// expand 展开错误字段, 返回展开后的字段及 console 编码时需要附加的调用栈
+func (c *errorCore) expand(fields []zapcore.Field) ([]zapcore.Field, []string) {
+	if !hasErrorField(fields) {
+		return fields, nil
+	}
+
+	var stacks []string
+	expanded := make([]zapcore.Field, 0, len(fields)+2)
+	for _, f := range fields {
+		err, ok := f.Interface.(error)
+		if f.Type != zapcore.ErrorType || !ok {
+			expanded = append(expanded, f)
+			continue
+		}
+
+		expanded = append(expanded, zap.String(f.Key, err.Error()))
+		if causes := zbase.ErrorCauses(err); len(causes) > 0 {
+			if c.console {
+				expanded = append(expanded, zap.Strings(f.Key+"Causes", errorMessages(causes)))
+			} else {
+				expanded = append(expanded, zap.Array(f.Key+"Causes", errorArray(causes)))
+			}
+		}
+		if stack, ok := zbase.ErrorStack(err); ok {
+			if c.console {
+				stacks = append(stacks, stack)
+			} else {
+				expanded = append(expanded, zap.String(f.Key+"Stack", stack))
+			}
+		}
+	}
+	return expanded, stacks
+}
+
+func errorMessages(errs []error) []string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	return msgs
+}
+
+// errorArray 将原因链编码为对象数组, 每个对象包含 error 字段, 对象为包含多个错误的错误时还包含 errorCauses 字段
+type errorArray []error
+
+func (errs errorArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if e := enc.AppendObject(errorObject{err}); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+type errorObject struct {
+	err error
+}
+
+func (o errorObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("error", o.err.Error())
+	if causes, ok := zbase.MultiErrors(o.err); ok && len(causes) > 0 {
+		return enc.AddArray("errorCauses", errorArray(causes))
+	}
+	return nil
+}
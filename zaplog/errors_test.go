@@ -0,0 +1,126 @@
+package zaplog
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zsink"
+)
+
+type tStackError struct {
+	msg string
+}
+
+func (e tStackError) Error() string { return e.msg }
+
+func (e tStackError) StackTrace() []string { return []string{"main.main", "runtime.main"} }
+
+type tMultiError []error
+
+func (e tMultiError) Error() string   { return fmt.Sprintf("%d errors", len(e)) }
+func (e tMultiError) Unwrap() []error { return e }
+
+func newErrorTestLogger(t *testing.T, encoding string) (*Logger, *tEntrySink) {
+	esink := &tEntrySink{}
+	scheme := "TestErrorCore" + encoding
+	err := zsink.RegisterSink(scheme, func(u *url.URL) (zap.Sink, error) {
+		return esink, nil
+	})
+	if err != nil {
+		t.Fatalf("register sink: %v", err)
+	}
+	encoder := NewJSONEncoderConfig()
+	if encoding == "console" {
+		encoder = NewConsoleEncoderConfig()
+	}
+	encoder.TimeKey = ""
+	cfg := Config{
+		Level: iface.DEBUG,
+		Cores: []CoreConfig{
+			{
+				Name:     "Test",
+				Encoding: encoding,
+				Encoder:  encoder,
+				MinLevel: iface.DEBUG,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{scheme + "://1"},
+			},
+		},
+		Loggers: []LoggerConfig{
+			{
+				DisableCaller: true,
+				Cores:         []string{"Test"},
+			},
+		},
+	}
+	logger, err := New(cfg)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	return logger, esink
+}
+
+func TestErrorCore(t *testing.T) {
+	e1 := tStackError{msg: "e1"}
+	e2 := fmt.Errorf("e2: %w", e1)
+	multi := tMultiError{errors.New("m1"), e2}
+
+	tests := []struct {
+		encoding string
+		want     []string
+	}{
+		{
+			encoding: "json",
+			want: []string{
+				`{"level":"info","msg":"plain","error":"EOF"}` + "\n",
+				`{"level":"info","msg":"chain","error":"e3: e2: e1","errorCauses":[{"error":"e2: e1"},{"error":"e1"}],"errorStack":"main.main\nruntime.main"}` + "\n",
+				`{"level":"info","msg":"multi","cause":"2 errors","causeCauses":[{"error":"m1"},{"error":"e2: e1"}]}` + "\n",
+				`{"level":"info","msg":"bound","error":"e2: e1","errorCauses":[{"error":"e1"}],"errorStack":"main.main\nruntime.main","k":1}` + "\n",
+				`{"level":"info","msg":"nested","error":"wrap: 2 errors","errorCauses":[{"error":"2 errors","errorCauses":[{"error":"m1"},{"error":"e2: e1"}]}]}` + "\n",
+			},
+		},
+		{
+			encoding: "console",
+			want: []string{
+				"INFO\tplain\t{\"error\": \"EOF\"}\n",
+				"INFO\tchain\t{\"error\": \"e3: e2: e1\", \"errorCauses\": [\"e2: e1\", \"e1\"]}\nmain.main\nruntime.main\n",
+				"INFO\tmulti\t{\"cause\": \"2 errors\", \"causeCauses\": [\"m1\", \"e2: e1\"]}\n",
+				"INFO\tbound\t{\"error\": \"e2: e1\", \"errorCauses\": [\"e1\"], \"k\": 1}\nmain.main\nruntime.main\n",
+				"INFO\tnested\t{\"error\": \"wrap: 2 errors\", \"errorCauses\": [\"2 errors\"]}\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		logger, esink := newErrorTestLogger(t, tt.encoding)
+		logger.Infow("plain", "error", errors.New("EOF"))
+		logger.Infow("chain", "error", fmt.Errorf("e3: %w", e2))
+		logger.Infow("multi", "cause", multi)
+		logger.WithArgs("error", e2).Infow("bound", "k", 1)
+		logger.Infow("nested", "error", fmt.Errorf("wrap: %w", multi))
+		logger.Close()
+
+		if got, want := len(esink.data), len(tt.want); got != want {
+			t.Fatalf("%s: data: got %d, want %d", tt.encoding, got, want)
+		}
+		for i, want := range tt.want {
+			if got := esink.data[i]; got != want {
+				t.Errorf("%s: %d: data:\n got: %q\nwant: %q", tt.encoding, i, got, want)
+			}
+		}
+	}
+}
+
+func TestErrorCoreFieldsUnchanged(t *testing.T) {
+	c := &errorCore{}
+	fields := []zapcore.Field{zap.String("k1", "v1"), zap.Int("k2", 2)}
+	got, stacks := c.expand(fields)
+	if &got[0] != &fields[0] || stacks != nil {
+		t.Errorf("expand: unexpected copy")
+	}
+}
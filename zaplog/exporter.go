@@ -0,0 +1,110 @@
+package zaplog
+
+import (
+	"bytes"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// PublishExpvar 将写入统计以 name 发布到 expvar, name 重复时 expvar.Publish 会 panic
+func (p *Logger) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return p.Stats()
+	}))
+}
+
+// WritePrometheus 以 Prometheus 文本格式输出写入统计
+func (p *Logger) WritePrometheus(w io.Writer) error {
+	_, err := w.Write(formatPrometheus(p.Stats()))
+	return err
+}
+
+// PrometheusHandler 返回以 Prometheus 文本格式输出写入统计的 http.Handler
+func (p *Logger) PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		p.WritePrometheus(w)
+	})
+}
+
+type metric struct {
+	name  string
+	typ   string
+	help  string
+	value func(s *SinkStats) float64
+}
+
+var metrics = []metric{
+	{
+		name:  "tlog_sink_written_bytes_total",
+		typ:   "counter",
+		help:  "Total number of bytes written to the sink.",
+		value: func(s *SinkStats) float64 { return float64(s.BytesWritten) },
+	},
+	{
+		name:  "tlog_sink_entries_total",
+		typ:   "counter",
+		help:  "Total number of entries written to the sink.",
+		value: func(s *SinkStats) float64 { return float64(s.Entries) },
+	},
+	{
+		name:  "tlog_sink_errors_total",
+		typ:   "counter",
+		help:  "Total number of write and sync errors of the sink.",
+		value: func(s *SinkStats) float64 { return float64(s.Errors) },
+	},
+	{
+		name: "tlog_sink_last_error_timestamp_seconds",
+		typ:  "gauge",
+		help: "Unix time of the last error of the sink, 0 if none.",
+		value: func(s *SinkStats) float64 {
+			if s.LastErrorTime.IsZero() {
+				return 0
+			}
+			return float64(s.LastErrorTime.UnixNano()) / 1e9
+		},
+	},
+	{
+		name:  "tlog_sink_rotations_total",
+		typ:   "counter",
+		help:  "Total number of file rotations of the sink.",
+		value: func(s *SinkStats) float64 { return float64(s.Rotations) },
+	},
+	{
+		name:  "tlog_sink_queue_depth",
+		typ:   "gauge",
+		help:  "Number of entries waiting in the sink queue.",
+		value: func(s *SinkStats) float64 { return float64(s.QueueDepth) },
+	},
+	{
+		name:  "tlog_sink_dropped_total",
+		typ:   "counter",
+		help:  "Total number of entries dropped by the sink.",
+		value: func(s *SinkStats) float64 { return float64(s.Dropped) },
+	},
+}
+
+func formatPrometheus(stats []CoreStats) []byte {
+	var buf bytes.Buffer
+	for _, m := range metrics {
+		fmt.Fprintf(&buf, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", m.name, m.typ)
+		for _, c := range stats {
+			for i := range c.Sinks {
+				s := &c.Sinks[i]
+				fmt.Fprintf(&buf, "%s{core=\"%s\",url=\"%s\"} %v\n",
+					m.name, escapeLabel(c.Name), escapeLabel(s.URL), m.value(s))
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
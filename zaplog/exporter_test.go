@@ -0,0 +1,81 @@
+package zaplog
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatPrometheus(t *testing.T) {
+	stats := []CoreStats{
+		{
+			Name: "Info",
+			Sinks: []SinkStats{
+				{
+					URL:           `rfile://workdir/"log"/info.log`,
+					BytesWritten:  1024,
+					Entries:       8,
+					Errors:        2,
+					LastErrorTime: time.Unix(1500000000, 0),
+					Rotations:     1,
+					QueueDepth:    3,
+					Dropped:       4,
+				},
+			},
+		},
+	}
+	text := string(formatPrometheus(stats))
+
+	labels := `{core="Info",url="rfile://workdir/\"log\"/info.log"}`
+	lines := []string{
+		"# TYPE tlog_sink_written_bytes_total counter",
+		"tlog_sink_written_bytes_total" + labels + " 1024",
+		"tlog_sink_entries_total" + labels + " 8",
+		"tlog_sink_errors_total" + labels + " 2",
+		"tlog_sink_last_error_timestamp_seconds" + labels + " 1.5e+09",
+		"tlog_sink_rotations_total" + labels + " 1",
+		"# TYPE tlog_sink_queue_depth gauge",
+		"tlog_sink_queue_depth" + labels + " 3",
+		"tlog_sink_dropped_total" + labels + " 4",
+	}
+	for _, line := range lines {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, text)
+		}
+	}
+}
+
+func TestPrometheusHandler(t *testing.T) {
+	logger, err := New(NewDevelopmentConfig())
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	logger.PrometheusHandler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if got, want := w.Header().Get("Content-Type"), "text/plain; version=0.0.4; charset=utf-8"; got != want {
+		t.Errorf("content type: got %q, want %q", got, want)
+	}
+	if want := `tlog_sink_entries_total{core="Stdout",url="stdout"} 0`; !strings.Contains(w.Body.String(), want) {
+		t.Errorf("missing %q in:\n%s", want, w.Body.String())
+	}
+}
+
+func TestPublishExpvar(t *testing.T) {
+	logger, err := New(NewDevelopmentConfig())
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	logger.PublishExpvar("TestPublishExpvar")
+
+	var stats []CoreStats
+	if err = json.Unmarshal([]byte(expvar.Get("TestPublishExpvar").String()), &stats); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Name != "Stdout" || stats[0].Sinks[0].URL != "stdout" {
+		t.Errorf("stats: got %+v", stats)
+	}
+}
@@ -0,0 +1,54 @@
+package zaplog
+
+import (
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/zaplog/zlogger"
+)
+
+// fatalCore 在输出 PANIC 及 FATAL 级别的日志后同步 Logger 的全部 core 及输出,
+// 避免 zap 调用 panic 或 os.Exit 时丢失缓冲中的日志; FATAL 级别时再调用 OnFatal 注册的函数及退出函数
+type fatalCore struct {
+	zapcore.Core
+	logger *Logger
+}
+
+func newFatalCore(core zapcore.Core, logger *Logger) zapcore.Core {
+	return &fatalCore{Core: core, logger: logger}
+}
+
+func (c *fatalCore) With(fields []zapcore.Field) zapcore.Core {
+	return &fatalCore{Core: c.Core.With(fields), logger: c.logger}
+}
+
+func (c *fatalCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < zapcore.DPanicLevel {
+		return c.Core.Check(ent, ce)
+	}
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *fatalCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	// 由各个 core 按自身的级别过滤
+	if ce := c.Core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+	c.logger.Sync()
+	if ent.Level == zapcore.FatalLevel {
+		c.logger.exitFatal()
+	}
+	return nil
+}
+
+func (p *Logger) exitFatal() {
+	for _, f := range p.fatal {
+		f()
+	}
+	p.exit(1)
+
+	// 退出函数返回时(如测试中拦截了退出)由 zlogger.Logger 恢复, 使 Fatal 正常返回
+	panic(zlogger.ExitReturned{})
+}
@@ -0,0 +1,100 @@
+package zaplog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+func newFatalTestLogger(t *testing.T, name string, opts ...Option) (*Logger, *tSink, *tSink) {
+	debug := RegisterTestSink(t, name+"Debug")
+	fatal := RegisterTestSink(t, name+"Fatal")
+	cfg := Config{
+		Level: iface.DEBUG,
+		Cores: []CoreConfig{
+			{
+				Name:     "Debug",
+				Encoding: "json",
+				Encoder:  NewJSONEncoderConfig(),
+				MinLevel: iface.DEBUG,
+				MaxLevel: iface.DEBUG,
+				URLs:     []string{name + "Debug://1"},
+			},
+			{
+				Name:     "Fatal",
+				Encoding: "json",
+				Encoder:  NewJSONEncoderConfig(),
+				MinLevel: iface.PANIC,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{name + "Fatal://1"},
+			},
+		},
+		Loggers: []LoggerConfig{
+			{
+				Cores: []string{"Debug", "Fatal"},
+			},
+		},
+	}
+	logger, err := New(cfg, opts...)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	return logger, debug, fatal
+}
+
+func TestFatalCoreFatal(t *testing.T) {
+	var calls []string
+	logger, debug, fatal := newFatalTestLogger(t, "TestFatalCoreFatal",
+		SetExitFunc(func(code int) { calls = append(calls, "exit") }),
+		OnFatal(func() { calls = append(calls, "hook1") }),
+		OnFatal(func() { calls = append(calls, "hook2") }),
+	)
+
+	logger.Debug("debug")
+	logger.Info("info")
+	assert.Equal(t, 1, debug.writeCount, "unexpected debug writes")
+	assert.Equal(t, 0, fatal.writeCount, "unexpected fatal writes")
+	assert.Equal(t, 0, debug.syncCount, "unexpected debug syncs")
+
+	logger.WithArgs("k", 1).Fatalw("fatal")
+	calls = append(calls, "returned")
+
+	assert.Equal(t, []string{"hook1", "hook2", "exit", "returned"}, calls, "unexpected calls")
+	assert.Equal(t, 1, debug.writeCount, "unexpected debug writes")
+	assert.Equal(t, 1, fatal.writeCount, "unexpected fatal writes")
+	assert.True(t, debug.syncCount > 0, "debug sink is not synced")
+	assert.True(t, fatal.syncCount > 0, "fatal sink is not synced")
+}
+
+func TestFatalCorePanic(t *testing.T) {
+	exited := false
+	logger, debug, fatal := newFatalTestLogger(t, "TestFatalCorePanic", SetExitFunc(func(code int) { exited = true }))
+
+	assert.PanicsWithValue(t, "panic", func() { logger.Panic("panic") }, "unexpected panic")
+	assert.False(t, exited, "unexpected exit")
+	assert.Equal(t, 0, debug.writeCount, "unexpected debug writes")
+	assert.Equal(t, 1, fatal.writeCount, "unexpected fatal writes")
+	assert.True(t, debug.syncCount > 0, "debug sink is not synced")
+	assert.True(t, fatal.syncCount > 0, "fatal sink is not synced")
+}
+
+func TestFatalCoreContext(t *testing.T) {
+	exited := 0
+	logger, _, fatal := newFatalTestLogger(t, "TestFatalCoreContext", SetExitFunc(func(code int) { exited++ }))
+
+	logger.FatalContext(context.Background(), "fatal")
+	logger.Named("child").Printf(0, iface.FATAL, "fatal %d", 1)
+	assert.Equal(t, 2, exited, "unexpected exits")
+	assert.Equal(t, 2, fatal.writeCount, "unexpected fatal writes")
+}
+
+func TestFatalCoreDisabled(t *testing.T) {
+	logger, debug, fatal := newFatalTestLogger(t, "TestFatalCoreDisabled")
+	logger.SetLevel(iface.FATAL)
+	logger.Error("error")
+	assert.Equal(t, 0, debug.writeCount+fatal.writeCount, "unexpected writes")
+	assert.Equal(t, 0, debug.syncCount+fatal.syncCount, "unexpected syncs")
+}
@@ -0,0 +1,127 @@
+package zaplog
+
+import (
+	"context"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zlogger"
+)
+
+// ChainContextHooks 按顺序组合多个 ContextHook;
+// 字段名重复时保留最后一个值, 输出位置为该字段第一次出现的位置;
+// 只有其中有 CoreHook 时, 返回的 ContextHook 才实现 CoreHook
+func ChainContextHooks(hooks ...ContextHook) ContextHook {
+	var chain contextHooks
+	for _, h := range hooks {
+		switch x := h.(type) {
+		case nil:
+		case contextHooks:
+			chain = append(chain, x...)
+		case coreContextHooks:
+			chain = append(chain, x.contextHooks...)
+		default:
+			chain = append(chain, x)
+		}
+	}
+	for _, h := range chain {
+		if _, ok := h.(zlogger.CoreHook); ok {
+			return coreContextHooks{chain}
+		}
+	}
+	return chain
+}
+
+type contextHooks []ContextHook
+
+func (hs contextHooks) WithContext(ctx context.Context) (args []interface{}) {
+	for _, h := range hs {
+		args = append(args, h.WithContext(ctx)...)
+	}
+	return dedupArgs(args)
+}
+
+// wrapsCore 返回是否有需要包装 core 的 CoreHook
+func (hs contextHooks) wrapsCore() bool {
+	for _, h := range hs {
+		if _, ok := zlogger.AsCoreHook(h); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (hs contextHooks) wrapCore(ctx context.Context, core zapcore.Core) zapcore.Core {
+	for _, h := range hs {
+		if ch, ok := zlogger.AsCoreHook(h); ok {
+			core = ch.WrapCore(ctx, core)
+		}
+	}
+	return core
+}
+
+// coreContextHooks 是包含 CoreHook 的 contextHooks
+type coreContextHooks struct {
+	contextHooks
+}
+
+func (hs coreContextHooks) WrapsCore() bool {
+	return hs.wrapsCore()
+}
+
+func (hs coreContextHooks) WrapCore(ctx context.Context, core zapcore.Core) zapcore.Core {
+	return hs.wrapCore(ctx, core)
+}
+
+// dedupArgs 去除重复的字段, args 中可以是键值对, 也可以是 zap.Field 或 iface.Field
+func dedupArgs(args []interface{}) []interface{} {
+	type pair struct {
+		key   string
+		items []interface{}
+	}
+
+	var pairs []pair
+	index := make(map[string]int)
+	dup := false
+	for i := 0; i < len(args); {
+		var p pair
+		switch x := args[i].(type) {
+		case zapcore.Field:
+			p = pair{key: x.Key, items: args[i : i+1]}
+			i++
+		case iface.Field:
+			p = pair{key: x.Key, items: args[i : i+1]}
+			i++
+		case string:
+			if i+1 >= len(args) {
+				p = pair{items: args[i:]}
+				i++
+				break
+			}
+			p = pair{key: x, items: args[i : i+2]}
+			i += 2
+		default:
+			p = pair{items: args[i : i+1]}
+			i++
+		}
+		if p.key != "" {
+			if j, ok := index[p.key]; ok {
+				pairs[j].items = p.items
+				dup = true
+				continue
+			}
+			index[p.key] = len(pairs)
+		}
+		pairs = append(pairs, p)
+	}
+	if !dup {
+		return args
+	}
+
+	result := make([]interface{}, 0, len(args))
+	for _, p := range pairs {
+		result = append(result, p.items...)
+	}
+	return result
+}
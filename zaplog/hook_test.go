@@ -0,0 +1,116 @@
+package zaplog
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zlogger"
+)
+
+func TestDedupArgs(t *testing.T) {
+	tests := []struct {
+		args []interface{}
+		want []interface{}
+	}{
+		{
+			args: nil,
+			want: nil,
+		},
+		{
+			args: []interface{}{"k1", 1, "k2", 2},
+			want: []interface{}{"k1", 1, "k2", 2},
+		},
+		{
+			args: []interface{}{"k1", 1, "k2", 2, "k1", 3},
+			want: []interface{}{"k1", 3, "k2", 2},
+		},
+		{
+			args: []interface{}{zap.Int("k1", 1), "k2", 2, "k1", 3, zap.Int("k2", 4)},
+			want: []interface{}{"k1", 3, zap.Int("k2", 4)},
+		},
+		{
+			args: []interface{}{"k1", 1, 100, "k1", 2, "dangling"},
+			want: []interface{}{"k1", 2, 100, "dangling"},
+		},
+		{
+			args: []interface{}{iface.Field{Key: "k1", Type: iface.Int64Type, Integer: 1}, "k2", 2, "k1", 3, iface.Field{Key: "k2", Type: iface.StringType, String: "v2"}},
+			want: []interface{}{"k1", 3, iface.Field{Key: "k2", Type: iface.StringType, String: "v2"}},
+		},
+	}
+	for i, tt := range tests {
+		if got, want := dedupArgs(tt.args), tt.want; !reflect.DeepEqual(got, want) {
+			t.Errorf("%d: args: got %v, want %v", i, got, want)
+		}
+	}
+}
+
+type tCoreHook struct {
+	name string
+}
+
+func (p *tCoreHook) WithContext(ctx context.Context) (args []interface{}) {
+	return []interface{}{"hook", p.name, p.name, true}
+}
+
+func (p *tCoreHook) WrapCore(ctx context.Context, core zapcore.Core) zapcore.Core {
+	return core.With([]zapcore.Field{zap.Bool("wrapped_"+p.name, true)})
+}
+
+func TestChainContextHooks(t *testing.T) {
+	h1 := &tCoreHook{name: "h1"}
+	h2 := ContextHookFunc(func(ctx context.Context) []interface{} {
+		return []interface{}{"h2", true}
+	})
+	h3 := &tCoreHook{name: "h3"}
+
+	hook := ChainContextHooks(ChainContextHooks(h1, nil, h2), h3)
+	if got, want := len(hook.(coreContextHooks).contextHooks), 3; got != want {
+		t.Fatalf("hooks: got %d, want %d", got, want)
+	}
+
+	args := hook.WithContext(context.Background())
+	if got, want := args, []interface{}{"hook", "h3", "h1", true, "h2", true, "h3", true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("args: got %v, want %v", got, want)
+	}
+
+	core := hook.(zlogger.CoreHook).WrapCore(context.Background(), zapcore.NewNopCore())
+	if core == nil {
+		t.Errorf("wrap core: got nil")
+	}
+}
+
+func TestChainContextHooksTypedFields(t *testing.T) {
+	h1 := ContextHookFunc(func(ctx context.Context) []interface{} {
+		return []interface{}{iface.Field{Key: "tenant", Type: iface.StringType, String: "t1"}, "user", 1}
+	})
+	h2 := ContextHookFunc(func(ctx context.Context) []interface{} {
+		return []interface{}{iface.Field{Key: "tenant", Type: iface.StringType, String: "t2"}}
+	})
+
+	args := ChainContextHooks(h1, h2).WithContext(context.Background())
+	if got, want := args, []interface{}{iface.Field{Key: "tenant", Type: iface.StringType, String: "t2"}, "user", 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("args: got %v, want %v", got, want)
+	}
+}
+
+func TestChainContextHooksWithoutCoreHook(t *testing.T) {
+	h1 := ContextHookFunc(func(ctx context.Context) []interface{} {
+		return []interface{}{"h1", true}
+	})
+	h2 := ContextHookFunc(func(ctx context.Context) []interface{} {
+		return []interface{}{"h2", true}
+	})
+
+	hook := ChainContextHooks(h1, h2)
+	if _, ok := hook.(zlogger.CoreHook); ok {
+		t.Errorf("hook implements CoreHook")
+	}
+	if _, ok := ChainContextHooks(hook, &tCoreHook{name: "h3"}).(zlogger.CoreHook); !ok {
+		t.Errorf("hook does not implement CoreHook")
+	}
+}
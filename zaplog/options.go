@@ -1,5 +1,7 @@
 package zaplog
 
+import "time"
+
 type Option func(*Logger)
 
 func SetContextHook(h ContextHook) Option {
@@ -7,3 +9,36 @@ func SetContextHook(h ContextHook) Option {
 		p.hook = h
 	}
 }
+
+// AddContextHook 追加 ContextHook, 与已设置的 ContextHook 按顺序组合
+func AddContextHook(h ContextHook) Option {
+	return func(p *Logger) {
+		if p.hook == nil {
+			p.hook = h
+			return
+		}
+		p.hook = ChainContextHooks(p.hook, h)
+	}
+}
+
+// SetClock 设置获取日志时间的函数, 默认为 time.Now, 可用于测试中固定日志时间
+func SetClock(now func() time.Time) Option {
+	return func(p *Logger) {
+		p.clock = now
+	}
+}
+
+// SetExitFunc 设置输出 FATAL 级别日志后调用的退出函数, 默认为 os.Exit;
+// 退出函数返回时 Fatal 等方法正常返回, 测试中可用于拦截退出
+func SetExitFunc(exit func(code int)) Option {
+	return func(p *Logger) {
+		p.exit = exit
+	}
+}
+
+// OnFatal 注册输出 FATAL 级别日志并同步输出后, 退出前调用的函数, 按注册顺序调用
+func OnFatal(f func()) Option {
+	return func(p *Logger) {
+		p.fatal = append(p.fatal, f)
+	}
+}
@@ -3,6 +3,7 @@ package zaplog
 import (
 	"context"
 	"testing"
+	"time"
 )
 
 type tContextHook struct {
@@ -22,3 +23,45 @@ func TestSetContextHook(t *testing.T) {
 		t.Fatalf("hook: got %v, want %v", got, want)
 	}
 }
+
+func TestAddContextHook(t *testing.T) {
+	var logger Logger
+	var h1, h2 tContextHook
+	AddContextHook(&h1)(&logger)
+	if got, want := logger.hook, &h1; got != want {
+		t.Fatalf("hook: got %v, want %v", got, want)
+	}
+	AddContextHook(&h2)(&logger)
+	logger.hook.WithContext(context.Background())
+	if h1.call != 1 || h2.call != 1 {
+		t.Errorf("call: got %d %d, want 1 1", h1.call, h2.call)
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	var logger Logger
+	now := time.Unix(1577808000, 0)
+	SetClock(func() time.Time { return now })(&logger)
+	if got, want := logger.clock(), now; !got.Equal(want) {
+		t.Errorf("clock: got %v, want %v", got, want)
+	}
+}
+
+func TestSetExitFunc(t *testing.T) {
+	var logger Logger
+	code := 0
+	SetExitFunc(func(c int) { code = c })(&logger)
+	logger.exit(2)
+	if got, want := code, 2; got != want {
+		t.Errorf("code: got %d, want %d", got, want)
+	}
+}
+
+func TestOnFatal(t *testing.T) {
+	var logger Logger
+	OnFatal(func() {})(&logger)
+	OnFatal(func() {})(&logger)
+	if got, want := len(logger.fatal), 2; got != want {
+		t.Errorf("fatal hooks: got %d, want %d", got, want)
+	}
+}
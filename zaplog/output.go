@@ -0,0 +1,81 @@
+package zaplog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	callRegexp  = regexp.MustCompile(`^(\w+)\s*\((.*)\)$`)
+	hostListReg = regexp.MustCompile(`^kafka://[^/?#]*$`) // 地址列表以逗号分隔且尚未结束的 URL
+)
+
+// parseOutput 解析 URLs 中的输出表达式, 如 failover(tcp://a:514, rfile://workdir/log/spool.log);
+// 不是组合表达式时返回单个输出
+func parseOutput(s string) (OutputConfig, error) {
+	s = strings.TrimSpace(s)
+	m := callRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return OutputConfig{URL: s}, nil
+	}
+
+	var cfg OutputConfig
+	if err := cfg.Mode.UnmarshalText([]byte(m[1])); err != nil {
+		return OutputConfig{}, err
+	}
+	args, err := splitOutputArgs(m[2])
+	if err != nil {
+		return OutputConfig{}, fmt.Errorf("parse %q: %w", s, err)
+	}
+	for _, arg := range args {
+		out, err := parseOutput(arg)
+		if err != nil {
+			return OutputConfig{}, err
+		}
+		cfg.Outputs = append(cfg.Outputs, out)
+	}
+	return cfg, nil
+}
+
+// splitOutputArgs 按顶层逗号拆分参数;
+// 逗号前是尚未结束的 kafka 地址列表时, 逗号视为 URL 的一部分, 如 kafka://broker1,broker2/topic
+func splitOutputArgs(s string) ([]string, error) {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	parts = append(parts, s[start:])
+
+	var args []string
+	for _, part := range parts {
+		if len(args) > 0 && hostListReg.MatchString(strings.TrimSpace(args[len(args)-1])) {
+			args[len(args)-1] += "," + part
+			continue
+		}
+		args = append(args, part)
+	}
+	for i, arg := range args {
+		if args[i] = strings.TrimSpace(arg); args[i] == "" {
+			return nil, fmt.Errorf("empty output")
+		}
+	}
+	return args, nil
+}
@@ -0,0 +1,88 @@
+package zaplog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOutput(t *testing.T) {
+	tests := []struct {
+		s   string
+		cfg OutputConfig
+		err string
+	}{
+		{
+			s:   "stdout",
+			cfg: OutputConfig{URL: "stdout"},
+		},
+		{
+			s:   " rfile://workdir/log/info.log ",
+			cfg: OutputConfig{URL: "rfile://workdir/log/info.log"},
+		},
+		{
+			s: "failover(unix:///var/run/a.sock, kafka://b1,b2/topic, rfile://workdir/log/spool.log)",
+			cfg: OutputConfig{
+				Mode: FailoverMode,
+				Outputs: []OutputConfig{
+					{URL: "unix:///var/run/a.sock"},
+					{URL: "kafka://b1,b2/topic"},
+					{URL: "rfile://workdir/log/spool.log"},
+				},
+			},
+		},
+		{
+			s: "fanout(stderr,roundrobin(/tmp/a.log, /tmp/b.log))",
+			cfg: OutputConfig{
+				Mode: FanoutMode,
+				Outputs: []OutputConfig{
+					{URL: "stderr"},
+					{
+						Mode: RoundRobinMode,
+						Outputs: []OutputConfig{
+							{URL: "/tmp/a.log"},
+							{URL: "/tmp/b.log"},
+						},
+					},
+				},
+			},
+		},
+		{
+			s: "fanout(stderr, out/app.log, kafka://b1:9092, b2:9092/logs?acks=1, logs/err.log)",
+			cfg: OutputConfig{
+				Mode: FanoutMode,
+				Outputs: []OutputConfig{
+					{URL: "stderr"},
+					{URL: "out/app.log"},
+					{URL: "kafka://b1:9092, b2:9092/logs?acks=1"},
+					{URL: "logs/err.log"},
+				},
+			},
+		},
+		{
+			s:   "random(stdout, stderr)",
+			err: "unrecognized output mode",
+		},
+		{
+			s:   "failover(stdout, )",
+			err: "empty output",
+		},
+		{
+			s:   "failover(stdout, fanout(stderr)))",
+			err: "unbalanced parentheses",
+		},
+	}
+	for i, tt := range tests {
+		cfg, err := parseOutput(tt.s)
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: got %v, want %v", i, err, tt.err)
+			continue
+		}
+		if err != nil {
+			t.Logf("%d: parse output: %v", i, err)
+			continue
+		}
+		if got, want := cfg, tt.cfg; !reflect.DeepEqual(got, want) {
+			t.Errorf("%d: config: got %+v, want %+v", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package zaplog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/zaplog/zlogger"
+)
+
+// 常用敏感信息的正则表达式, 可用作 RedactRule.Pattern
+const (
+	CardNumberPattern = `\b(?:\d{4}[ -]?){3}\d{4}(?:\d{3})?\b`
+	EmailPattern      = `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`
+	PhonePattern      = `\b1[3-9]\d{9}\b`
+	IDCardPattern     = `\b\d{17}[\dXx]\b`
+)
+
+const (
+	fullMask   = "******"
+	hashPrefix = "sha256:"
+	hashLen    = 16
+)
+
+func (m MaskStrategy) mask(s string) string {
+	switch m {
+	case PartialMask:
+		n := utf8.RuneCountInString(s)
+		keep := n / 4
+		if keep > 4 {
+			keep = 4
+		}
+		if keep <= 0 {
+			return strings.Repeat("*", n)
+		}
+		runes := []rune(s)
+		return string(runes[:keep]) + strings.Repeat("*", n-2*keep) + string(runes[n-keep:])
+	case HashMask:
+		sum := sha256.Sum256([]byte(s))
+		return hashPrefix + hex.EncodeToString(sum[:])[:hashLen]
+	default:
+		return fullMask
+	}
+}
+
+type valueRule struct {
+	re   *regexp.Regexp
+	mask MaskStrategy
+}
+
+// redactor 按字段名及正则表达式对日志消息和字段脱敏
+type redactor struct {
+	keys  map[string]MaskStrategy
+	rules []valueRule
+	types sync.Map // reflect.Type -> bool, 该类型的值是否可能需要脱敏
+}
+
+func newRedactor(rules []RedactRule) (*redactor, error) {
+	r := &redactor{keys: make(map[string]MaskStrategy)}
+	for i, rule := range rules {
+		if len(rule.Keys) <= 0 && rule.Pattern == "" {
+			return nil, fmt.Errorf("redact rule %d: no keys or pattern", i)
+		}
+		for _, key := range rule.Keys {
+			r.keys[strings.ToLower(key)] = rule.Mask
+		}
+		if rule.Pattern != "" {
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("redact rule %d: %w", i, err)
+			}
+			r.rules = append(r.rules, valueRule{re: re, mask: rule.Mask})
+		}
+	}
+	return r, nil
+}
+
+func (r *redactor) keyMask(key string) (MaskStrategy, bool) {
+	if len(r.keys) <= 0 {
+		return 0, false
+	}
+	key = strings.ToLower(key)
+	if m, ok := r.keys[key]; ok {
+		return m, true
+	}
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		m, ok := r.keys[key[i+1:]]
+		return m, ok
+	}
+	return 0, false
+}
+
+func (r *redactor) redactString(s string) string {
+	for _, rule := range r.rules {
+		s = rule.re.ReplaceAllStringFunc(s, rule.mask.mask)
+	}
+	return s
+}
+
+// redactFields 返回脱敏后的字段, 没有字段被修改时返回原切片
+func (r *redactor) redactFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		nf, ok := r.redactField(f)
+		if !ok {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, nf)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func (r *redactor) redactField(f zapcore.Field) (zapcore.Field, bool) {
+	if f.Type == zapcore.NamespaceType || f.Type == zapcore.SkipType {
+		return f, false
+	}
+	if m, ok := r.keyMask(f.Key); ok {
+		return zap.String(f.Key, m.mask(fieldString(f))), true
+	}
+	switch f.Type {
+	case zapcore.StringType:
+		if s := r.redactString(f.String); s != f.String {
+			return zap.String(f.Key, s), true
+		}
+	case zapcore.ByteStringType, zapcore.StringerType, zapcore.ErrorType:
+		v := fieldString(f)
+		if s := r.redactString(v); s != v {
+			return zap.String(f.Key, s), true
+		}
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		if v, ok := r.redactValue(enc.Fields[f.Key]); ok {
+			return zap.Reflect(f.Key, v), true
+		}
+	case zapcore.ReflectType:
+		if f.Interface == nil || !r.mayRedact(reflect.TypeOf(f.Interface)) {
+			return f, false
+		}
+		var v interface{}
+		b, err := json.Marshal(f.Interface)
+		if err != nil || json.Unmarshal(b, &v) != nil {
+			return f, false
+		}
+		if v, ok := r.redactValue(v); ok {
+			return zap.Reflect(f.Key, v), true
+		}
+	}
+	return f, false
+}
+
+// redactValue 对对象或数组编码后的值脱敏, 返回值是否被修改
+func (r *redactor) redactValue(v interface{}) (interface{}, bool) {
+	switch x := v.(type) {
+	case string:
+		s := r.redactString(x)
+		return s, s != x
+	case map[string]interface{}:
+		changed := false
+		for k, e := range x {
+			if m, ok := r.keyMask(k); ok {
+				x[k] = m.mask(fmt.Sprint(e))
+				changed = true
+			} else if ne, ok := r.redactValue(e); ok {
+				x[k] = ne
+				changed = true
+			}
+		}
+		return x, changed
+	case []interface{}:
+		changed := false
+		for i, e := range x {
+			if ne, ok := r.redactValue(e); ok {
+				x[i] = ne
+				changed = true
+			}
+		}
+		return x, changed
+	}
+	return v, false
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// mayRedact 返回 t 类型的值编码后是否可能包含需要脱敏的内容, 结果按类型缓存,
+// 不可能包含时不必对 zap.Any 的值做 JSON 编解码
+func (r *redactor) mayRedact(t reflect.Type) bool {
+	if v, ok := r.types.Load(t); ok {
+		return v.(bool)
+	}
+	may := r.typeMayRedact(t, make(map[reflect.Type]bool))
+	r.types.Store(t, may)
+	return may
+}
+
+func (r *redactor) typeMayRedact(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	// 自定义编码的结果未知
+	pt := reflect.PtrTo(t)
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return len(r.rules) > 0
+	case reflect.Interface:
+		return true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return len(r.rules) > 0
+		}
+		return r.typeMayRedact(t.Elem(), seen)
+	case reflect.Ptr, reflect.Array:
+		return r.typeMayRedact(t.Elem(), seen)
+	case reflect.Map:
+		return len(r.keys) > 0 || r.typeMayRedact(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" && !f.Anonymous {
+				continue
+			}
+			name, opts := f.Name, ""
+			if tag, ok := f.Tag.Lookup("json"); ok {
+				if tag == "-" {
+					continue
+				}
+				if i := strings.IndexByte(tag, ','); i >= 0 {
+					tag, opts = tag[:i], tag[i:]
+				}
+				if tag != "" {
+					name = tag
+				}
+			}
+			if _, ok := r.keyMask(name); ok {
+				return true
+			}
+			if strings.Contains(opts, ",string") && len(r.rules) > 0 {
+				return true
+			}
+			if r.typeMayRedact(f.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldString 返回字段值的字符串形式
+func fieldString(f zapcore.Field) string {
+	switch f.Type {
+	case zapcore.StringType:
+		return f.String
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok {
+			return err.Error()
+		}
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	switch v := enc.Fields[f.Key].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// newRedactCore 创建对日志消息及字段脱敏后再写入 core 的 core
+func newRedactCore(core zapcore.Core, r *redactor) zapcore.Core {
+	return &redactCore{Core: core, r: r}
+}
+
+type redactCore struct {
+	zapcore.Core
+	r *redactor
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.r.redactFields(fields)), r: c.r}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if len(c.r.rules) > 0 {
+		ent.Message = c.r.redactString(ent.Message)
+	}
+	// 由内层的 core 检查脱敏后的日志条目, 如设置日志时间, 按各个 core 的级别过滤
+	if ce := c.Core.Check(ent, nil); ce != nil {
+		ce.Write(c.r.redactFields(fields)...)
+	}
+	return nil
+}
+
+// newRedactHook 使 CoreHook 包装脱敏之前的 core, 其添加的 core (如 span 事件) 只得到脱敏后的日志
+func newRedactHook(hook ContextHook) ContextHook {
+	if _, ok := hook.(zlogger.CoreHook); !ok {
+		return hook
+	}
+	return redactHook{ContextHook: hook}
+}
+
+type redactHook struct {
+	ContextHook
+}
+
+func (h redactHook) WrapsCore() bool {
+	_, ok := zlogger.AsCoreHook(h.ContextHook)
+	return ok
+}
+
+func (h redactHook) WrapCore(ctx context.Context, core zapcore.Core) zapcore.Core {
+	ch, ok := zlogger.AsCoreHook(h.ContextHook)
+	if !ok {
+		return core
+	}
+	if rc, ok := core.(*redactCore); ok {
+		return &redactCore{Core: ch.WrapCore(ctx, rc.Core), r: rc.r}
+	}
+	return ch.WrapCore(ctx, core)
+}
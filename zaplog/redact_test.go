@@ -0,0 +1,374 @@
+package zaplog
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zsink"
+)
+
+type tUser struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	Email    string `json:"email"`
+}
+
+func (u tUser) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", u.Name)
+	enc.AddString("password", u.Password)
+	enc.AddString("email", u.Email)
+	return nil
+}
+
+func TestMaskStrategyMask(t *testing.T) {
+	tests := []struct {
+		m    MaskStrategy
+		s    string
+		want string
+	}{
+		{m: FullMask, s: "secret", want: "******"},
+		{m: FullMask, s: "", want: "******"},
+		{m: PartialMask, s: "13812345678", want: "13*******78"},
+		{m: PartialMask, s: "6222020012345678", want: "6222********5678"},
+		{m: PartialMask, s: "abc", want: "***"},
+		{m: PartialMask, s: "张三丰是谁", want: "张***谁"},
+		{m: HashMask, s: "secret", want: "sha256:2bb80d537b1da3e3"},
+	}
+	for i, tt := range tests {
+		if got, want := tt.m.mask(tt.s), tt.want; got != want {
+			t.Errorf("%d: mask %v: got %q, want %q", i, tt.m, got, want)
+		}
+	}
+}
+
+func TestNewRedactor(t *testing.T) {
+	tests := []struct {
+		rules []RedactRule
+		err   string
+	}{
+		{rules: nil},
+		{rules: []RedactRule{{Keys: []string{"password"}}, {Pattern: EmailPattern}}},
+		{rules: []RedactRule{{Mask: HashMask}}, err: "redact rule 0: no keys or pattern"},
+		{rules: []RedactRule{{Keys: []string{"token"}}, {Pattern: "("}}, err: "redact rule 1: error parsing regexp"},
+	}
+	for i, tt := range tests {
+		_, err := newRedactor(tt.rules)
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: got %v, want %v", i, err, tt.err)
+			continue
+		}
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    []string
+	}{
+		{pattern: CardNumberPattern, s: "card 6222 0200 1234 5678, order 20200101", want: []string{"6222 0200 1234 5678"}},
+		{pattern: CardNumberPattern, s: "card 6222020012345678901", want: []string{"6222020012345678901"}},
+		{pattern: EmailPattern, s: "mail to a.b+c@example.com.", want: []string{"a.b+c@example.com"}},
+		{pattern: PhonePattern, s: "phone 13812345678, id 213812345678", want: []string{"13812345678"}},
+		{pattern: IDCardPattern, s: "id 11010519491231002X", want: []string{"11010519491231002X"}},
+	}
+	for i, tt := range tests {
+		got := regexp.MustCompile(tt.pattern).FindAllString(tt.s, -1)
+		assert.Equal(t, tt.want, got, "%d: unexpected matches", i)
+	}
+}
+
+func TestRedactCore(t *testing.T) {
+	r, err := newRedactor([]RedactRule{
+		{Keys: []string{"password", "Token"}},
+		{Keys: []string{"phone"}, Mask: PartialMask},
+		{Pattern: EmailPattern, Mask: HashMask},
+	})
+	if err != nil {
+		t.Fatalf("new redactor: %v", err)
+	}
+	observed, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newRedactCore(observed, r))
+
+	user := tUser{Name: "tom", Password: "123456", Email: "tom@example.com"}
+	email := HashMask.mask("tom@example.com")
+	logger.With(zap.String("token", "abc")).Info("login tom@example.com",
+		zap.String("Password", "123456"),
+		zap.Int("phone", 13812345678),
+		zap.String("req.password", "123456"),
+		zap.String("note", "contact tom@example.com"),
+		zap.Error(errors.New("bad email tom@example.com")),
+		zap.Object("user", user),
+		zap.Any("users", []tUser{user}),
+		zap.String("name", "tom"),
+	)
+	logger.Debug("plain", zap.String("name", "tom"))
+
+	want := []observer.LoggedEntry{
+		{
+			Entry: zapcore.Entry{Level: zapcore.InfoLevel, Message: "login " + email},
+			Context: []zapcore.Field{
+				zap.String("token", "******"),
+				zap.String("Password", "******"),
+				zap.String("phone", "13*******78"),
+				zap.String("req.password", "******"),
+				zap.String("note", "contact "+email),
+				zap.String("error", "bad email "+email),
+				zap.Reflect("user", map[string]interface{}{"name": "tom", "password": "******", "email": email}),
+				zap.Reflect("users", []interface{}{map[string]interface{}{"name": "tom", "password": "******", "email": email}}),
+				zap.String("name", "tom"),
+			},
+		},
+		{
+			Entry:   zapcore.Entry{Level: zapcore.DebugLevel, Message: "plain"},
+			Context: []zapcore.Field{zap.String("name", "tom")},
+		},
+	}
+	assert.Equal(t, want, logs.AllUntimed(), "unexpected log entries")
+}
+
+func TestRedactFieldsUnchanged(t *testing.T) {
+	r, err := newRedactor([]RedactRule{{Keys: []string{"password"}}})
+	if err != nil {
+		t.Fatalf("new redactor: %v", err)
+	}
+	fields := []zapcore.Field{zap.String("k1", "v1"), zap.Int("k2", 2)}
+	got := r.redactFields(fields)
+	if &got[0] != &fields[0] {
+		t.Errorf("redact fields: unexpected copy")
+	}
+}
+
+func TestLoggerRedact(t *testing.T) {
+	esink := &tEntrySink{}
+	err := zsink.RegisterSink("TestLoggerRedact", func(u *url.URL) (zap.Sink, error) {
+		return esink, nil
+	})
+	if err != nil {
+		t.Fatalf("register sink: %v", err)
+	}
+
+	cfg := Config{
+		Level: iface.DEBUG,
+		Cores: []CoreConfig{
+			{
+				Name:     "Test",
+				Encoding: "json",
+				Encoder:  NewJSONEncoderConfig(),
+				MinLevel: iface.DEBUG,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{"TestLoggerRedact://1"},
+				Redact: []RedactRule{
+					{Keys: []string{"password"}},
+					{Pattern: PhonePattern, Mask: PartialMask},
+				},
+			},
+		},
+		Loggers: []LoggerConfig{
+			{
+				DisableCaller: true,
+				Cores:         []string{"Test"},
+			},
+		},
+	}
+	logger, err := New(cfg)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	logger.WithArgs("password", "123456").Infof("call %s", "13812345678")
+	logger.Close()
+
+	if got, want := len(esink.data), 1; got != want {
+		t.Fatalf("data: got %d, want %d", got, want)
+	}
+	assert.Regexp(t, `"msg":"call 13\*{7}78","password":"\*{6}"`, esink.data[0], "unexpected output")
+
+	cfg.Cores[0].Redact = []RedactRule{{Pattern: "["}}
+	if _, err = New(cfg); !matchError(t, err, "new redactor: redact rule 0") {
+		t.Errorf("new: unexpected error %v", err)
+	}
+}
+
+func TestLoggerRedactPerCore(t *testing.T) {
+	audit, public := &tEntrySink{}, &tEntrySink{}
+	for scheme, sink := range map[string]*tEntrySink{"TestLoggerRedactPerCoreAudit": audit, "TestLoggerRedactPerCorePublic": public} {
+		sink := sink
+		err := zsink.RegisterSink(scheme, func(u *url.URL) (zap.Sink, error) {
+			return sink, nil
+		})
+		if err != nil {
+			t.Fatalf("register sink: %v", err)
+		}
+	}
+
+	cfg := Config{
+		Level: iface.DEBUG,
+		Cores: []CoreConfig{
+			{
+				Name:     "Audit",
+				Encoding: "json",
+				Encoder:  NewJSONEncoderConfig(),
+				MinLevel: iface.DEBUG,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{"TestLoggerRedactPerCoreAudit://1"},
+			},
+			{
+				Name:     "Public",
+				Encoding: "json",
+				Encoder:  NewJSONEncoderConfig(),
+				MinLevel: iface.DEBUG,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{"TestLoggerRedactPerCorePublic://1"},
+				Redact:   []RedactRule{{Keys: []string{"password"}}},
+			},
+		},
+		Loggers: []LoggerConfig{
+			{
+				DisableCaller: true,
+				Cores:         []string{"Audit", "Public"},
+			},
+		},
+	}
+	logger, err := New(cfg)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	logger.WithArgs("user", "alice").Infow("login", "password", "123456")
+	logger.Close()
+
+	if len(audit.data) != 1 || len(public.data) != 1 {
+		t.Fatalf("data: got %d %d, want 1 1", len(audit.data), len(public.data))
+	}
+	assert.Regexp(t, `"user":"alice","password":"123456"`, audit.data[0], "unexpected audit output")
+	assert.Regexp(t, `"user":"alice","password":"\*{6}"`, public.data[0], "unexpected public output")
+
+	cfg.Loggers[0].Redact = []RedactRule{{Pattern: "["}}
+	if _, err = New(cfg); !matchError(t, err, "new redactor: redact rule 0") {
+		t.Errorf("new: unexpected error %v", err)
+	}
+}
+
+// tTeeHook 将日志同时写入 core, 与 zotel 的 span 事件相同
+type tTeeHook struct {
+	core zapcore.Core
+}
+
+func (h tTeeHook) WithContext(ctx context.Context) []interface{} {
+	return []interface{}{"token", "abc"}
+}
+
+func (h tTeeHook) WrapCore(ctx context.Context, core zapcore.Core) zapcore.Core {
+	return zapcore.NewTee(core, h.core)
+}
+
+func TestLoggerRedactHookCore(t *testing.T) {
+	esink := &tEntrySink{}
+	err := zsink.RegisterSink("TestLoggerRedactHookCore", func(u *url.URL) (zap.Sink, error) {
+		return esink, nil
+	})
+	if err != nil {
+		t.Fatalf("register sink: %v", err)
+	}
+	cfg := Config{
+		Level: iface.DEBUG,
+		Cores: []CoreConfig{
+			{
+				Name:     "Test",
+				Encoding: "json",
+				Encoder:  NewJSONEncoderConfig(),
+				MinLevel: iface.DEBUG,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{"TestLoggerRedactHookCore://1"},
+				Redact:   []RedactRule{{Pattern: PhonePattern, Mask: PartialMask}},
+			},
+		},
+		Loggers: []LoggerConfig{
+			{
+				DisableCaller: true,
+				Cores:         []string{"Test"},
+				Redact:        []RedactRule{{Keys: []string{"password", "token"}}},
+			},
+		},
+	}
+	observed, logs := observer.New(zapcore.DebugLevel)
+	logger, err := New(cfg, SetContextHook(tTeeHook{core: observed}))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	logger.WithArgs("password", "123456").WithContext(context.Background()).Infof("call %s", "13812345678")
+	logger.InfoContext(context.Background(), "login", "password", "123456")
+	logger.Close()
+
+	// hook 添加的 core 只使用日志对象的脱敏规则
+	want := []observer.LoggedEntry{
+		{
+			Entry:   zapcore.Entry{Level: zapcore.InfoLevel, Message: "call 13812345678"},
+			Context: []zapcore.Field{zap.String("token", "******"), zap.String("password", "******")},
+		},
+		{
+			Entry:   zapcore.Entry{Level: zapcore.InfoLevel, Message: "login"},
+			Context: []zapcore.Field{zap.String("token", "******"), zap.String("password", "******")},
+		},
+	}
+	assert.Equal(t, want, logs.AllUntimed(), "unexpected hook core entries")
+	if got, want := len(esink.data), 2; got != want {
+		t.Fatalf("data: got %d, want %d", got, want)
+	}
+	assert.Regexp(t, `"msg":"call 13\*{7}78","token":"\*{6}","password":"\*{6}"`, esink.data[0], "unexpected output")
+}
+
+func TestRedactorMayRedact(t *testing.T) {
+	type tPoint struct {
+		X, Y int
+	}
+	type tSecret struct {
+		Token int `json:"token"`
+	}
+	type tNode struct {
+		ID   int
+		Next *tNode
+	}
+
+	keys, err := newRedactor([]RedactRule{{Keys: []string{"token"}}})
+	if err != nil {
+		t.Fatalf("new redactor: %v", err)
+	}
+	patterns, err := newRedactor([]RedactRule{{Pattern: PhonePattern}})
+	if err != nil {
+		t.Fatalf("new redactor: %v", err)
+	}
+
+	tests := []struct {
+		v        interface{}
+		keys     bool
+		patterns bool
+	}{
+		{v: 1, keys: false, patterns: false},
+		{v: tPoint{}, keys: false, patterns: false},
+		{v: []tPoint{}, keys: false, patterns: false},
+		{v: &tNode{}, keys: false, patterns: false},
+		{v: tSecret{}, keys: true, patterns: false},
+		{v: []*tSecret{}, keys: true, patterns: false},
+		{v: tUser{}, keys: false, patterns: true},
+		{v: []byte("13812345678"), keys: false, patterns: true},
+		{v: map[string]int{}, keys: true, patterns: false},
+		{v: []interface{}{}, keys: true, patterns: true},
+		{v: time.Time{}, keys: true, patterns: true},
+	}
+	for i, tt := range tests {
+		typ := reflect.TypeOf(tt.v)
+		assert.Equal(t, tt.keys, keys.mayRedact(typ), "%d: %s: keys", i, typ)
+		assert.Equal(t, tt.patterns, patterns.mayRedact(typ), "%d: %s: patterns", i, typ)
+	}
+}
@@ -1,29 +1,199 @@
 package zaplog
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
-	_ "github.com/ironzhang/tlog/zaplog/zsink"
+	"github.com/ironzhang/tlog/zaplog/zsink"
 )
 
-func newSinks(urls []string) (zap.Sink, error) {
-	ws, cf, err := zap.Open(urls...)
+func newSinks(urls []string, outputs []OutputConfig) (*sinks, error) {
+	cfg := OutputConfig{Mode: FanoutMode, Outputs: outputs}
+	for _, url := range urls {
+		out, err := parseOutput(url)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Outputs = append(cfg.Outputs, out)
+	}
+
+	var ss sinks
+	sink, err := ss.open(cfg)
 	if err != nil {
+		ss.leaves.Close()
 		return nil, err
 	}
-	return &sink{
-		WriteSyncer: ws,
-		closef:      cf,
-	}, nil
+	ss.Sink = sink
+	return &ss, nil
+}
+
+// sinks 是一个 core 的输出, 由各个叶子输出按配置组合而成
+type sinks struct {
+	zap.Sink
+	leaves leaves
+}
+
+func (ss *sinks) open(cfg OutputConfig) (zap.Sink, error) {
+	if cfg.URL != "" {
+		if len(cfg.Outputs) > 0 {
+			return nil, fmt.Errorf("output %q: url and outputs are both set", cfg.URL)
+		}
+		s, err := zsink.Open(cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		leaf := &statSink{url: cfg.URL, sink: s}
+		ss.leaves = append(ss.leaves, leaf)
+		return leaf, nil
+	}
+
+	children := make([]zap.Sink, 0, len(cfg.Outputs))
+	weights := make([]int, 0, len(cfg.Outputs))
+	for _, out := range cfg.Outputs {
+		s, err := ss.open(out)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, s)
+		weights = append(weights, out.Weight)
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	switch cfg.Mode {
+	case FanoutMode:
+		return zsink.Fanout(children...), nil
+	case FailoverMode:
+		return zsink.Failover(children...), nil
+	case RoundRobinMode:
+		return zsink.RoundRobin(children, weights), nil
+	default:
+		return nil, fmt.Errorf("unknown output mode %v", cfg.Mode)
+	}
+}
+
+func (ss *sinks) WriteEntry(ent zapcore.Entry, fields []zapcore.Field, b []byte) (int, error) {
+	return zsink.WriteEntry(ss.Sink, ent, fields, b)
+}
+
+// hasEntrySink 返回是否有输出实现了 zsink.EntrySink
+func (ss *sinks) hasEntrySink() bool {
+	for _, s := range ss.leaves {
+		if _, ok := s.sink.(zsink.EntrySink); ok {
+			return true
+		}
+	}
+	return false
 }
 
-type sink struct {
-	zapcore.WriteSyncer
-	closef func()
+func (ss *sinks) stats() []SinkStats {
+	return ss.leaves.stats()
 }
 
-func (s *sink) Close() error {
-	s.closef()
-	return nil
+type leaves []*statSink
+
+func (ls leaves) Close() (err error) {
+	for _, s := range ls {
+		err = multierr.Append(err, s.Close())
+	}
+	return err
+}
+
+func (ls leaves) stats() []SinkStats {
+	stats := make([]SinkStats, 0, len(ls))
+	for _, s := range ls {
+		stats = append(stats, s.stats())
+	}
+	return stats
+}
+
+// statSink 统计单个输出的写入情况, 同时保证对输出的串行访问
+type statSink struct {
+	url  string
+	sink zap.Sink
+
+	mu          sync.Mutex
+	bytes       int64
+	entries     int64
+	errors      int64
+	lastErr     error
+	lastErrTime time.Time
+}
+
+func (s *statSink) Write(b []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.sink.Write(b)
+	s.count(n, err)
+	return n, err
+}
+
+func (s *statSink) WriteEntry(ent zapcore.Entry, fields []zapcore.Field, b []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := zsink.WriteEntry(s.sink, ent, fields, b)
+	s.count(n, err)
+	return n, err
+}
+
+func (s *statSink) count(n int, err error) {
+	s.bytes += int64(n)
+	s.entries++
+	if err != nil {
+		s.fail(err)
+	}
+}
+
+func (s *statSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.sink.Sync()
+	if err != nil {
+		s.fail(err)
+	}
+	return err
+}
+
+func (s *statSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sink.Close()
+}
+
+func (s *statSink) fail(err error) {
+	s.errors++
+	s.lastErr = err
+	s.lastErrTime = time.Now()
+}
+
+func (s *statSink) stats() SinkStats {
+	s.mu.Lock()
+	st := SinkStats{
+		URL:           s.url,
+		BytesWritten:  s.bytes,
+		Entries:       s.entries,
+		Errors:        s.errors,
+		LastErrorTime: s.lastErrTime,
+	}
+	if s.lastErr != nil {
+		st.LastError = s.lastErr.Error()
+	}
+	s.mu.Unlock()
+
+	if r, ok := s.sink.(zsink.Rotator); ok {
+		st.Rotations = r.Rotations()
+	}
+	if q, ok := s.sink.(zsink.Queuer); ok {
+		st.QueueDepth = q.QueueDepth()
+		st.Dropped = q.Dropped()
+	}
+	return st
 }
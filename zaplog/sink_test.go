@@ -1,10 +1,14 @@
 package zaplog
 
 import (
+	"errors"
 	"net/url"
+	"reflect"
 	"testing"
 
 	"go.uber.org/zap"
+
+	"github.com/ironzhang/tlog/zaplog/zsink"
 )
 
 type tSink struct {
@@ -43,7 +47,7 @@ func TestSink(t *testing.T) {
 	tsink := RegisterTestSink(t, "TestSink")
 
 	urls := []string{"TestSink://1", "TestSink://2"}
-	sink, err := newSinks(urls)
+	sink, err := newSinks(urls, nil)
 	if err != nil {
 		t.Fatalf("new sinks: %v", err)
 	}
@@ -62,3 +66,75 @@ func TestSink(t *testing.T) {
 	}
 	t.Logf("writeCount: %d, syncCount: %d, closeCount: %d", tsink.writeCount, tsink.syncCount, tsink.closeCount)
 }
+
+func TestSinksCompose(t *testing.T) {
+	err := zsink.RegisterSink("TestSinksCompose", func(u *url.URL) (zap.Sink, error) {
+		if u.Host == "bad" {
+			return &tStatSink{err: errors.New("bad sink")}, nil
+		}
+		return &tStatSink{}, nil
+	})
+	if err != nil {
+		t.Fatalf("register sink: %v", err)
+	}
+
+	urls := []string{"failover(TestSinksCompose://bad, TestSinksCompose://good)"}
+	outputs := []OutputConfig{
+		{
+			Mode: RoundRobinMode,
+			Outputs: []OutputConfig{
+				{URL: "TestSinksCompose://rr1", Weight: 2},
+				{URL: "TestSinksCompose://rr2"},
+			},
+		},
+	}
+	sink, err := newSinks(urls, outputs)
+	if err != nil {
+		t.Fatalf("new sinks: %v", err)
+	}
+	defer sink.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err = sink.Write([]byte("x")); err != nil {
+			t.Errorf("write: %v", err)
+		}
+	}
+
+	entries := make(map[string]int64)
+	errs := make(map[string]int64)
+	for _, s := range sink.stats() {
+		entries[s.URL] = s.Entries
+		errs[s.URL] = s.Errors
+	}
+	want := map[string]int64{
+		"TestSinksCompose://rr1":  2,
+		"TestSinksCompose://rr2":  1,
+		"TestSinksCompose://bad":  3,
+		"TestSinksCompose://good": 3,
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries: got %v, want %v", entries, want)
+	}
+	if got, want := errs["TestSinksCompose://bad"], int64(3); got != want {
+		t.Errorf("errors: got %d, want %d", got, want)
+	}
+}
+
+func TestSinksError(t *testing.T) {
+	tests := []struct {
+		urls    []string
+		outputs []OutputConfig
+		err     string
+	}{
+		{urls: []string{"unknown(stdout)"}, err: "unrecognized output mode"},
+		{urls: []string{"stdout", "TestSinksError://1"}, err: "no sink found"},
+		{outputs: []OutputConfig{{URL: "stdout", Outputs: []OutputConfig{{URL: "stderr"}}}}, err: "both set"},
+		{outputs: []OutputConfig{{Mode: -1, Outputs: []OutputConfig{{URL: "stdout"}, {URL: "stderr"}}}}, err: "unknown output mode"},
+	}
+	for i, tt := range tests {
+		_, err := newSinks(tt.urls, tt.outputs)
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: got %v, want %v", i, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package zaplog
+
+import "time"
+
+// SinkStats 是单个输出的写入统计
+type SinkStats struct {
+	URL           string    `json:"url"`
+	BytesWritten  int64     `json:"bytesWritten"`
+	Entries       int64     `json:"entries"`
+	Errors        int64     `json:"errors"`
+	LastError     string    `json:"lastError,omitempty"`
+	LastErrorTime time.Time `json:"lastErrorTime"`
+	Rotations     int64     `json:"rotations"`
+	QueueDepth    int       `json:"queueDepth"`
+	Dropped       int64     `json:"dropped"`
+}
+
+// CoreStats 是一个 core 下所有输出的写入统计
+type CoreStats struct {
+	Name  string      `json:"name"`
+	Sinks []SinkStats `json:"sinks"`
+}
+
+// Stats 按配置顺序返回各个 core 的写入统计
+func (p *Logger) Stats() []CoreStats {
+	stats := make([]CoreStats, 0, len(p.outputs))
+	for _, o := range p.outputs {
+		stats = append(stats, CoreStats{Name: o.core, Sinks: o.sinks.stats()})
+	}
+	return stats
+}
@@ -0,0 +1,133 @@
+package zaplog
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zsink"
+)
+
+type tStatSink struct {
+	err error
+}
+
+func (p *tStatSink) Write(b []byte) (int, error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+	return len(b), nil
+}
+
+func (p *tStatSink) Sync() error {
+	return nil
+}
+
+func (p *tStatSink) Close() error {
+	return nil
+}
+
+func (p *tStatSink) Rotations() int64 {
+	return 3
+}
+
+func (p *tStatSink) QueueDepth() int {
+	return 4
+}
+
+func (p *tStatSink) Dropped() int64 {
+	return 5
+}
+
+func TestLoggerStats(t *testing.T) {
+	err := zsink.RegisterSink("TestLoggerStats", func(u *url.URL) (zap.Sink, error) {
+		if u.Host == "bad" {
+			return &tStatSink{err: errors.New("bad sink")}, nil
+		}
+		return &tStatSink{}, nil
+	})
+	if err != nil {
+		t.Fatalf("register sink: %v", err)
+	}
+	RegisterTestSink(t, "TestLoggerStatsZap")
+
+	cfg := Config{
+		Level: iface.DEBUG,
+		Cores: []CoreConfig{
+			{
+				Name:     "Good",
+				MinLevel: iface.DEBUG,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{"TestLoggerStats://good", "TestLoggerStatsZap://1"},
+			},
+			{
+				Name:     "Bad",
+				MinLevel: iface.ERROR,
+				MaxLevel: iface.FATAL,
+				URLs:     []string{"TestLoggerStats://bad"},
+			},
+		},
+		Loggers: []LoggerConfig{
+			{
+				Cores: []string{"Good", "Bad"},
+			},
+		},
+	}
+	logger, err := New(cfg)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("info")
+	logger.Error("error")
+
+	stats := logger.Stats()
+	if got, want := len(stats), 2; got != want {
+		t.Fatalf("core stats: got %d, want %d", got, want)
+	}
+
+	good := stats[0]
+	if got, want := good.Name, "Good"; got != want {
+		t.Errorf("name: got %q, want %q", got, want)
+	}
+	if got, want := len(good.Sinks), 2; got != want {
+		t.Fatalf("sink stats: got %d, want %d", got, want)
+	}
+	s := good.Sinks[0]
+	if got, want := s.URL, "TestLoggerStats://good"; got != want {
+		t.Errorf("url: got %q, want %q", got, want)
+	}
+	if got, want := s.Entries, int64(2); got != want {
+		t.Errorf("entries: got %d, want %d", got, want)
+	}
+	if s.BytesWritten <= 0 {
+		t.Errorf("bytes written: got %d, want > 0", s.BytesWritten)
+	}
+	if s.Errors != 0 || s.LastError != "" {
+		t.Errorf("errors: got %d %q, want none", s.Errors, s.LastError)
+	}
+	if s.Rotations != 3 || s.QueueDepth != 4 || s.Dropped != 5 {
+		t.Errorf("rotations, queue depth, dropped: got %d %d %d, want 3 4 5", s.Rotations, s.QueueDepth, s.Dropped)
+	}
+	if got, want := good.Sinks[1].Entries, int64(2); got != want {
+		t.Errorf("zap sink entries: got %d, want %d", got, want)
+	}
+
+	bad := stats[1].Sinks[0]
+	if got, want := bad.Entries, int64(1); got != want {
+		t.Errorf("entries: got %d, want %d", got, want)
+	}
+	if got, want := bad.Errors, int64(1); got != want {
+		t.Errorf("errors: got %d, want %d", got, want)
+	}
+	if got, want := bad.LastError, "bad sink"; got != want {
+		t.Errorf("last error: got %q, want %q", got, want)
+	}
+	if bad.LastErrorTime.IsZero() {
+		t.Errorf("last error time is zero")
+	}
+}
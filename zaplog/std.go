@@ -2,25 +2,142 @@ package zaplog
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
 )
 
-var stdLogger *Logger
+var (
+	stdOnce    sync.Once
+	stdLogger  *Logger
+	stdCreated int32
+)
 
-func init() {
-	hook := func(ctx context.Context) (args []interface{}) {
-		return StdContextHook(ctx)
+// newStdLogger 按 NewEnvConfig 创建 StdLogger, 环境变量配置有误时输出错误并使用 NewDevelopmentConfig
+func newStdLogger() *Logger {
+	cfg, err := NewEnvConfig()
+	if err == nil {
+		var logger *Logger
+		if logger, err = New(cfg, stdOptions()...); err == nil {
+			return logger
+		}
 	}
-	logger, err := New(NewDevelopmentConfig(), SetContextHook(ContextHookFunc(hook)))
+	fmt.Fprintf(os.Stderr, "zaplog: std logger: %v, use development config\n", err)
+
+	logger, err := New(NewDevelopmentConfig(), stdOptions()...)
 	if err != nil {
 		panic(err)
 	}
-	stdLogger = logger
+	return logger
 }
 
 var StdContextHook = func(ctx context.Context) (args []interface{}) {
 	return nil
 }
 
+var (
+	stdMu    sync.RWMutex
+	stdHooks contextHooks
+)
+
+// AddStdContextHook 为 StdLogger 追加 ContextHook, 在 StdContextHook 之后按注册顺序执行
+func AddStdContextHook(h ContextHook) {
+	stdMu.Lock()
+	stdHooks = append(stdHooks, h)
+	stdMu.Unlock()
+}
+
+var (
+	stdExit  = os.Exit // 由 stdMu 保护
+	stdFatal []func()
+)
+
+// SetStdExitFunc 设置 StdLogger 输出 FATAL 级别日志后调用的退出函数, exit 为 nil 时恢复为 os.Exit, 参见 SetExitFunc
+func SetStdExitFunc(exit func(code int)) {
+	if exit == nil {
+		exit = os.Exit
+	}
+	stdMu.Lock()
+	stdExit = exit
+	stdMu.Unlock()
+}
+
+// OnStdFatal 为 StdLogger 注册输出 FATAL 级别日志后, 退出前调用的函数, 参见 OnFatal
+func OnStdFatal(f func()) {
+	stdMu.Lock()
+	stdFatal = append(stdFatal, f)
+	stdMu.Unlock()
+}
+
+// stdOptions 返回 StdLogger 的选项, 退出函数及 OnStdFatal 注册的函数在 FATAL 时读取,
+// 在 StdLogger 创建前后设置都有效
+func stdOptions() []Option {
+	return []Option{
+		SetContextHook(stdHook{}),
+		SetExitFunc(func(code int) {
+			stdMu.RLock()
+			exit := stdExit
+			stdMu.RUnlock()
+			exit(code)
+		}),
+		OnFatal(func() {
+			stdMu.RLock()
+			fatal := stdFatal
+			stdMu.RUnlock()
+			for _, f := range fatal {
+				f()
+			}
+		}),
+	}
+}
+
+// stdHook 在每次调用时读取 StdContextHook 及已注册的 ContextHook
+type stdHook struct{}
+
+func (stdHook) hooks() contextHooks {
+	stdMu.RLock()
+	defer stdMu.RUnlock()
+	return stdHooks
+}
+
+func (h stdHook) WithContext(ctx context.Context) (args []interface{}) {
+	args = StdContextHook(ctx)
+	hooks := h.hooks()
+	if len(hooks) <= 0 {
+		return args
+	}
+	args = args[:len(args):len(args)]
+	for _, hook := range hooks {
+		args = append(args, hook.WithContext(ctx)...)
+	}
+	return dedupArgs(args)
+}
+
+// WrapsCore 返回已注册的 ContextHook 中是否有 CoreHook, 没有时日志对象不需要包装 core
+func (h stdHook) WrapsCore() bool {
+	return h.hooks().wrapsCore()
+}
+
+func (h stdHook) WrapCore(ctx context.Context, core zapcore.Core) zapcore.Core {
+	return h.hooks().wrapCore(ctx, core)
+}
+
+// StdLogger 返回默认的日志对象, 在首次调用时根据环境变量创建, 参见 NewEnvConfig
 func StdLogger() *Logger {
+	stdOnce.Do(func() {
+		stdLogger = newStdLogger()
+		atomic.StoreInt32(&stdCreated, 1)
+	})
 	return stdLogger
 }
+
+// CreatedStdLogger 返回已经创建的 StdLogger, 尚未创建时返回 false, 不会因此创建 StdLogger
+func CreatedStdLogger() (*Logger, bool) {
+	if atomic.LoadInt32(&stdCreated) == 0 {
+		return nil, false
+	}
+	return stdLogger, true
+}
@@ -2,7 +2,10 @@ package zaplog
 
 import (
 	"context"
+	"reflect"
 	"testing"
+
+	"github.com/ironzhang/tlog/zaplog/zlogger"
 )
 
 func TestStdContextHook(t *testing.T) {
@@ -21,3 +24,89 @@ func TestStdContextHook(t *testing.T) {
 		t.Errorf("call: got %v, want %v", call, 2)
 	}
 }
+
+func TestAddStdContextHook(t *testing.T) {
+	defer func() {
+		stdHooks = nil
+	}()
+
+	var hook tContextHook
+	AddStdContextHook(&hook)
+	AddStdContextHook(ContextHookFunc(func(ctx context.Context) []interface{} {
+		return []interface{}{"tenant", "t1"}
+	}))
+
+	args := stdHook{}.WithContext(context.Background())
+	if got, want := len(args), 2; got != want {
+		t.Errorf("args: got %v, want %d items", args, want)
+	}
+	StdLogger().WithContext(context.Background()).Info("add std context hook")
+	if got, want := hook.call, 2; got != want {
+		t.Errorf("call: got %v, want %v", got, want)
+	}
+}
+
+func TestStdHookWrapsCore(t *testing.T) {
+	defer func() {
+		stdHooks = nil
+	}()
+
+	if _, ok := zlogger.AsCoreHook(stdHook{}); ok {
+		t.Errorf("std hook wraps core without core hooks")
+	}
+	AddStdContextHook(ContextHookFunc(func(ctx context.Context) []interface{} {
+		return nil
+	}))
+	if _, ok := zlogger.AsCoreHook(stdHook{}); ok {
+		t.Errorf("std hook wraps core without core hooks")
+	}
+	AddStdContextHook(&tCoreHook{name: "h1"})
+	if _, ok := zlogger.AsCoreHook(stdHook{}); !ok {
+		t.Errorf("std hook does not wrap core with core hooks")
+	}
+}
+
+func TestStdLoggerWithContextAllocs(t *testing.T) {
+	defer func(hook func(context.Context) []interface{}) {
+		StdContextHook = hook
+	}(StdContextHook)
+	StdContextHook = func(ctx context.Context) []interface{} {
+		return nil
+	}
+
+	logger := StdLogger()
+	ctx := context.Background()
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.WithContext(ctx)
+	})
+	if allocs != 0 {
+		t.Errorf("allocs: got %v, want 0", allocs)
+	}
+}
+
+func TestSetStdExitFunc(t *testing.T) {
+	defer func() {
+		SetStdExitFunc(nil)
+		stdFatal = nil
+	}()
+
+	var calls []string
+	SetStdExitFunc(func(code int) {
+		calls = append(calls, "exit")
+	})
+	OnStdFatal(func() {
+		calls = append(calls, "fatal")
+	})
+	StdLogger().Fatal("std fatal")
+	if got, want := calls, []string{"fatal", "exit"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("calls: got %v, want %v", got, want)
+	}
+}
+
+func TestCreatedStdLogger(t *testing.T) {
+	logger := StdLogger()
+	created, ok := CreatedStdLogger()
+	if !ok || created != logger {
+		t.Errorf("created std logger: got %p %v, want %p true", created, ok, logger)
+	}
+}
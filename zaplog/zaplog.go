@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"time"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -26,13 +28,22 @@ func (f ContextHookFunc) WithContext(ctx context.Context) (args []interface{}) {
 type Logger struct {
 	hook  ContextHook
 	level zap.AtomicLevel
+	clock func() time.Time
+	exit  func(code int)
+	fatal []func()
 
 	*zlogger.Logger
 	closers []io.Closer
+	outputs []output
 	cores   map[string]zapcore.Core
 	loggers map[string]*zlogger.Logger
 }
 
+type output struct {
+	core  string
+	sinks *sinks
+}
+
 func New(cfg Config, opts ...Option) (*Logger, error) {
 	var logger Logger
 	if err := logger.init(cfg, opts); err != nil {
@@ -41,11 +52,32 @@ func New(cfg Config, opts ...Option) (*Logger, error) {
 	return &logger, nil
 }
 
-func (p *Logger) init(cfg Config, opts []Option) (err error) {
-	p.level = zap.NewAtomicLevelAt(zbase.ZapLevel(cfg.Level))
+// NewWithCore 使用 core 创建日志对象, 如测试中使用 zaptest/observer 记录日志;
+// 日志级别初始为 DEBUG, 同样支持 SetExitFunc, OnFatal 及 SetClock 等选项, Close 时不关闭 core
+func NewWithCore(core zapcore.Core, opts ...Option) *Logger {
+	var p Logger
+	p.level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	p.applyOptions(opts)
+
+	core = &levelCore{Core: core, level: p.level}
+	p.cores = map[string]zapcore.Core{"": core}
+	p.Logger = p.newLogger("", core, nil, []zap.Option{zap.AddCaller()})
+	p.loggers = map[string]*zlogger.Logger{"": p.Logger}
+	return &p
+}
+
+func (p *Logger) applyOptions(opts []Option) {
 	for _, apply := range opts {
 		apply(p)
 	}
+	if p.exit == nil {
+		p.exit = os.Exit
+	}
+}
+
+func (p *Logger) init(cfg Config, opts []Option) (err error) {
+	p.level = zap.NewAtomicLevelAt(zbase.ZapLevel(cfg.Level))
+	p.applyOptions(opts)
 
 	p.closers = make([]io.Closer, 0, len(cfg.Cores))
 	p.cores = make(map[string]zapcore.Core)
@@ -85,7 +117,12 @@ func (p *Logger) openCore(cfg CoreConfig) error {
 		return fmt.Errorf("new encoder: %w", err)
 	}
 
-	sink, err := newSinks(cfg.URLs)
+	r, err := newRedactor(cfg.Redact)
+	if err != nil {
+		return fmt.Errorf("new redactor: %w", err)
+	}
+
+	sink, err := newSinks(cfg.URLs, cfg.Outputs)
 	if err != nil {
 		return fmt.Errorf("new sinks: %w", err)
 	}
@@ -97,7 +134,18 @@ func (p *Logger) openCore(cfg CoreConfig) error {
 	}
 
 	p.closers = append(p.closers, sink)
-	p.cores[cfg.Name] = zapcore.NewCore(enc, sink, enab)
+	p.outputs = append(p.outputs, output{core: cfg.Name, sinks: sink})
+	var core zapcore.Core
+	if sink.hasEntrySink() {
+		core = newEntryCore(enc, sink, enab)
+	} else {
+		core = zapcore.NewCore(enc, sink, enab)
+	}
+	core = newErrorCore(core, cfg.Encoding)
+	if len(cfg.Redact) > 0 {
+		core = newRedactCore(core, r)
+	}
+	p.cores[cfg.Name] = core
 
 	return nil
 }
@@ -112,12 +160,32 @@ func (p *Logger) openLogger(cfg LoggerConfig) error {
 		return fmt.Errorf("combine core: %w", err)
 	}
 
-	opts := buildLoggerOptions(cfg)
-	p.loggers[cfg.Name] = zlogger.New(cfg.Name, core, p.hook, opts...)
+	var r *redactor
+	if len(cfg.Redact) > 0 {
+		if r, err = newRedactor(cfg.Redact); err != nil {
+			return fmt.Errorf("new redactor: %w", err)
+		}
+	}
+	p.loggers[cfg.Name] = p.newLogger(cfg.Name, core, r, buildLoggerOptions(cfg))
 
 	return nil
 }
 
+// newLogger 使用 core 创建 zlogger.Logger, PANIC 及 FATAL 级别时同步输出并调用退出函数;
+// r 为日志对象的脱敏规则, 不为 nil 时在最外层脱敏, 使 ContextHook 添加的 core (如 span 事件) 同样只得到脱敏后的日志
+func (p *Logger) newLogger(name string, core zapcore.Core, r *redactor, opts []zap.Option) *zlogger.Logger {
+	core = newFatalCore(core, p)
+	if p.clock != nil {
+		core = newClockCore(core, p.clock)
+	}
+	hook := p.hook
+	if r != nil {
+		core = newRedactCore(core, r)
+		hook = newRedactHook(hook)
+	}
+	return zlogger.New(name, core, hook, opts...)
+}
+
 func (p *Logger) combineCore(names []string) (zapcore.Core, error) {
 	cores := make([]zapcore.Core, 0, len(names))
 	for _, name := range names {
@@ -1,8 +1,12 @@
 package zaplog
 
 import (
+	"reflect"
 	"testing"
 
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
 	"github.com/ironzhang/tlog/iface"
 )
 
@@ -111,3 +115,29 @@ func TestLoggerClose(t *testing.T) {
 //		}
 //	}
 //}
+
+func TestNewWithCore(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	exited := 0
+	logger := NewWithCore(core, SetExitFunc(func(code int) { exited++ }))
+
+	logger.Debug("debug")
+	logger.SetLevel(iface.WARN)
+	logger.Info("info")
+	logger.Named("child").Warn("warn")
+	logger.Fatal("fatal")
+
+	var messages []string
+	for _, e := range logs.All() {
+		messages = append(messages, e.Message)
+	}
+	if got, want := messages, []string{"debug", "warn", "fatal"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("messages: got %q, want %q", got, want)
+	}
+	if got, want := exited, 1; got != want {
+		t.Errorf("exited: got %d, want %d", got, want)
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("close: %v", err)
+	}
+}
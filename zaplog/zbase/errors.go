@@ -0,0 +1,124 @@
+package zbase
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// ErrorCauses 返回 err 的原因链: 依次 Unwrap 得到的错误, 跳过与上一个错误信息相同的包装;
+// err 实现 Unwrap() []error 或 Errors() []error 时返回其包含的各个错误
+func ErrorCauses(err error) []error {
+	if errs, ok := MultiErrors(err); ok {
+		return errs
+	}
+
+	var causes []error
+	msg := err.Error()
+	for {
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return causes
+		}
+		if err = u.Unwrap(); err == nil {
+			return causes
+		}
+		if m := err.Error(); m != msg {
+			causes = append(causes, err)
+			msg = m
+		}
+		if _, ok = MultiErrors(err); ok {
+			return causes
+		}
+	}
+}
+
+// MultiErrors 返回 err 包含的多个错误, err 需实现 Unwrap() []error 或 Errors() []error
+func MultiErrors(err error) ([]error, bool) {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		return e.Unwrap(), true
+	case interface{ Errors() []error }:
+		return e.Errors(), true
+	}
+	return nil, false
+}
+
+// ErrorStack 返回 err 及其原因链中第一个带有调用栈的错误的调用栈,
+// 带有调用栈的错误实现了 StackTrace 方法, 如 github.com/pkg/errors 创建的错误,
+// StackTrace 返回 string 或 []string 时直接输出, 否则以 %+v 格式化
+func ErrorStack(err error) (string, bool) {
+	for err != nil {
+		if st, ok := stackTrace(err); ok {
+			return st, true
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return "", false
+		}
+		err = u.Unwrap()
+	}
+	return "", false
+}
+
+func stackTrace(err error) (string, bool) {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return "", false
+	}
+	var st string
+	switch v := m.Call(nil)[0].Interface().(type) {
+	case string:
+		st = v
+	case []string:
+		st = strings.Join(v, "\n")
+	default:
+		st = fmt.Sprintf("%+v", v)
+	}
+	st = strings.Trim(st, "\n")
+	return st, st != ""
+}
+
+// WithStack 返回记录了调用栈的 err, skip 为 0 时从 WithStack 的调用者开始记录;
+// 返回的错误与 err 的错误信息相同, 可以通过 Unwrap 得到 err
+func WithStack(err error, skip int) error {
+	var pcs [32]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	return &stackError{error: err, stack: pcs[:n]}
+}
+
+type stackError struct {
+	error
+	stack Stack
+}
+
+func (e *stackError) Unwrap() error {
+	return e.error
+}
+
+func (e *stackError) StackTrace() Stack {
+	return e.stack
+}
+
+// Stack 是调用栈的程序计数器, 以 %+v 格式化时每一帧输出为 "函数\n\t文件:行号"
+type Stack []uintptr
+
+func (s Stack) Format(st fmt.State, verb rune) {
+	if len(s) <= 0 {
+		return
+	}
+	frames := runtime.CallersFrames(s)
+	for {
+		frame, more := frames.Next()
+		if st.Flag('+') {
+			fmt.Fprintf(st, "%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+		} else {
+			fmt.Fprintf(st, "%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return
+		}
+		fmt.Fprint(st, "\n")
+	}
+}
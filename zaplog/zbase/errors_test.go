@@ -0,0 +1,124 @@
+package zbase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type tMultiError []error
+
+func (e tMultiError) Error() string   { return fmt.Sprintf("%d errors", len(e)) }
+func (e tMultiError) Unwrap() []error { return e }
+
+type tGroupError []error
+
+func (e tGroupError) Error() string   { return fmt.Sprintf("%d errors", len(e)) }
+func (e tGroupError) Errors() []error { return e }
+
+type tWrapError struct {
+	err error
+}
+
+func (e tWrapError) Error() string { return e.err.Error() }
+func (e tWrapError) Unwrap() error { return e.err }
+
+type tStackTrace []string
+
+func (s tStackTrace) Format(st fmt.State, verb rune) {
+	for _, frame := range s {
+		fmt.Fprintf(st, "\n%s", frame)
+	}
+}
+
+type tStackError struct {
+	msg   string
+	stack tStackTrace
+}
+
+func (e tStackError) Error() string           { return e.msg }
+func (e tStackError) StackTrace() tStackTrace { return e.stack }
+
+func messages(errs []error) []string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return msgs
+}
+
+func TestErrorCauses(t *testing.T) {
+	e1 := errors.New("e1")
+	e2 := fmt.Errorf("e2: %w", e1)
+	e3 := fmt.Errorf("e3: %w", e2)
+	multi := tMultiError{e1, e2}
+	tests := []struct {
+		err    error
+		causes []string
+	}{
+		{err: e1, causes: []string{}},
+		{err: e2, causes: []string{"e1"}},
+		{err: e3, causes: []string{"e2: e1", "e1"}},
+		{err: tWrapError{e3}, causes: []string{"e2: e1", "e1"}},
+		{err: fmt.Errorf("wrap: %w", tWrapError{e2}), causes: []string{"e2: e1", "e1"}},
+		{err: multi, causes: []string{"e1", "e2: e1"}},
+		{err: tGroupError{e1, e3}, causes: []string{"e1", "e3: e2: e1"}},
+		{err: fmt.Errorf("wrap: %w", multi), causes: []string{"2 errors"}},
+	}
+	for i, tt := range tests {
+		if got, want := messages(ErrorCauses(tt.err)), tt.causes; !reflect.DeepEqual(got, want) {
+			t.Errorf("%d: causes: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestErrorStack(t *testing.T) {
+	serr := tStackError{msg: "stack", stack: tStackTrace{"f1", "f2"}}
+	tests := []struct {
+		err   error
+		stack string
+		ok    bool
+	}{
+		{err: errors.New("e1"), stack: "", ok: false},
+		{err: serr, stack: "f1\nf2", ok: true},
+		{err: fmt.Errorf("wrap: %w", serr), stack: "f1\nf2", ok: true},
+		{err: tStackError{msg: "empty"}, stack: "", ok: false},
+	}
+	for i, tt := range tests {
+		stack, ok := ErrorStack(tt.err)
+		if stack != tt.stack || ok != tt.ok {
+			t.Errorf("%d: stack: got (%q, %v), want (%q, %v)", i, stack, ok, tt.stack, tt.ok)
+		}
+	}
+}
+
+func TestWithStack(t *testing.T) {
+	err := errors.New("e1")
+	serr := WithStack(err, 0)
+	if got, want := serr.Error(), "e1"; got != want {
+		t.Errorf("error: got %q, want %q", got, want)
+	}
+	if !errors.Is(serr, err) {
+		t.Errorf("errors.Is: got false, want true")
+	}
+	if got := ErrorCauses(serr); len(got) != 0 {
+		t.Errorf("causes: got %q, want empty", messages(got))
+	}
+
+	stack, ok := ErrorStack(serr)
+	if !ok {
+		t.Fatalf("stack: not found")
+	}
+	lines := strings.Split(stack, "\n")
+	if got, want := lines[0], "github.com/ironzhang/tlog/zaplog/zbase.TestWithStack"; got != want {
+		t.Errorf("function: got %q, want %q", got, want)
+	}
+	if got, want := lines[1], "errors_test.go:"; !strings.HasPrefix(got, "\t") || !strings.Contains(got, want) {
+		t.Errorf("file: got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%v", Stack(nil)), ""; got != want {
+		t.Errorf("empty stack: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package zlogger
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+func newBenchmarkLogger(level zapcore.Level) *Logger {
+	cfg := zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		LevelKey:       "level",
+		TimeKey:        "ts",
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.EpochTimeEncoder,
+		EncodeDuration: zapcore.NanosDurationEncoder,
+	}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(cfg), zapcore.AddSync(ioutil.Discard), level)
+	return New("", core, nil)
+}
+
+func boundArgs(n int) []interface{} {
+	args := make([]interface{}, 0, 2*n)
+	for i := 0; i < n; i++ {
+		args = append(args, fmt.Sprintf("key%d", i), i)
+	}
+	return args
+}
+
+func BenchmarkInfow(b *testing.B) {
+	for _, n := range []int{0, 5, 20} {
+		b.Run(fmt.Sprintf("%dBoundFields", n), func(b *testing.B) {
+			logger := newBenchmarkLogger(zapcore.DebugLevel).WithArgs(boundArgs(n)...)
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					logger.Infow("benchmark")
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkInfowWithKeyValues(b *testing.B) {
+	logger := newBenchmarkLogger(zapcore.DebugLevel).WithArgs(boundArgs(5)...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Infow("benchmark", "k1", "v1", "k2", 2)
+		}
+	})
+}
+
+func BenchmarkDisabledInfow(b *testing.B) {
+	logger := newBenchmarkLogger(zapcore.WarnLevel).WithArgs(boundArgs(5)...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Infow("benchmark", "k1", "v1")
+		}
+	})
+}
+
+func BenchmarkPrintc(b *testing.B) {
+	logger := newBenchmarkLogger(zapcore.DebugLevel).WithArgs(boundArgs(5)...).(*Logger)
+	ctx := iface.ContextWithArgs(context.Background(), "request", "r1")
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Printc(ctx, 0, iface.INFO, "benchmark")
+		}
+	})
+}
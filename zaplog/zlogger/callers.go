@@ -0,0 +1,32 @@
+package zlogger
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// maxCachedSkip 缓存的最大调用深度
+const maxCachedSkip = 10
+
+// callers 缓存各个调用深度的日志对象, 避免每次输出日志时复制日志对象
+type callers struct {
+	base  *zap.Logger
+	cache [maxCachedSkip]atomic.Value
+}
+
+func newCallers(base *zap.Logger) *callers {
+	return &callers{base: base}
+}
+
+func (c *callers) logger(skip int) *zap.Logger {
+	if skip < 0 || skip >= maxCachedSkip {
+		return c.base.WithOptions(zap.AddCallerSkip(skip))
+	}
+	if l, ok := c.cache[skip].Load().(*zap.Logger); ok {
+		return l
+	}
+	l := c.base.WithOptions(zap.AddCallerSkip(skip))
+	c.cache[skip].Store(l)
+	return l
+}
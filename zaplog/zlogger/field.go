@@ -0,0 +1,213 @@
+package zlogger
+
+import (
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+// objectMarshaler 将 iface.ObjectMarshaler 适配为 zapcore.ObjectMarshaler
+type objectMarshaler struct {
+	m iface.ObjectMarshaler
+}
+
+func (p objectMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return p.m.MarshalLogObject(enc)
+}
+
+// zapField 将 iface.Field 转换为 zap 字段, 基本类型不经过反射
+func zapField(f iface.Field) zapcore.Field {
+	switch f.Type {
+	case iface.SkipType:
+		return zap.Skip()
+	case iface.StringType:
+		return zap.String(f.Key, f.String)
+	case iface.Int64Type:
+		return zap.Int64(f.Key, f.Integer)
+	case iface.Uint64Type:
+		return zap.Uint64(f.Key, uint64(f.Integer))
+	case iface.Float64Type:
+		return zap.Float64(f.Key, math.Float64frombits(uint64(f.Integer)))
+	case iface.BoolType:
+		return zap.Bool(f.Key, f.Integer == 1)
+	case iface.DurationType:
+		return zap.Duration(f.Key, time.Duration(f.Integer))
+	case iface.TimeType:
+		return zapcore.Field{Key: f.Key, Type: zapcore.TimeType, Integer: f.Integer, Interface: f.Interface}
+	case iface.ErrorType:
+		if err, ok := f.Interface.(error); ok {
+			return zap.NamedError(f.Key, err)
+		}
+	case iface.ObjectType:
+		if m, ok := f.Interface.(iface.ObjectMarshaler); ok {
+			return zap.Object(f.Key, objectMarshaler{m: m})
+		}
+	}
+	v := f.Interface
+	if valuer, ok := v.(iface.Valuer); ok {
+		v = iface.Resolve(valuer)
+	}
+	if m, ok := v.(iface.ObjectMarshaler); ok {
+		return zap.Object(f.Key, objectMarshaler{m: m})
+	}
+	return zap.Any(f.Key, v)
+}
+
+// valuerField 返回 iface.Valuer 计算后的字段, 与直接输出计算结果时的编码方式相同
+func valuerField(key string, v iface.Valuer) zapcore.Field {
+	return zap.Any(key, iface.Resolve(v))
+}
+
+// needConvert 判断 kvs 中是否有需要转换的 iface.Field 或 iface.Valuer
+func needConvert(kvs []interface{}) bool {
+	for i := 0; i < len(kvs); i++ {
+		switch kvs[i].(type) {
+		case zapcore.Field:
+			continue
+		case iface.Field:
+			return true
+		}
+		if i+1 < len(kvs) {
+			if _, ok := kvs[i+1].(iface.Valuer); ok {
+				return true
+			}
+		}
+		i++
+	}
+	return false
+}
+
+// zapArgs 将 kvs 中的 iface.Field 转换为 zap 字段, 并计算值为 iface.Valuer 的键值对;
+// 不需要转换时返回 kvs
+func zapArgs(kvs []interface{}) []interface{} {
+	if !needConvert(kvs) {
+		return kvs
+	}
+
+	out := make([]interface{}, 0, len(kvs))
+	for i := 0; i < len(kvs); i++ {
+		switch x := kvs[i].(type) {
+		case zapcore.Field:
+			out = append(out, x)
+			continue
+		case iface.Field:
+			out = append(out, zapField(x))
+			continue
+		}
+		if i+1 >= len(kvs) {
+			out = append(out, kvs[i])
+			continue
+		}
+		key, ok := kvs[i].(string)
+		valuer, isValuer := kvs[i+1].(iface.Valuer)
+		if ok && isValuer {
+			out = append(out, valuerField(key, valuer))
+		} else {
+			out = append(out, kvs[i], kvs[i+1])
+		}
+		i++
+	}
+	return out
+}
+
+// zapFields 将 kvs 转换为 zap 字段, 并计算值为 iface.Valuer 的键值对;
+// 与 SugaredLogger 相同, 忽略没有值的键及非字符串的键, 并通过 logger 输出错误日志
+func zapFields(logger *zap.Logger, kvs []interface{}) []zapcore.Field {
+	if len(kvs) <= 0 {
+		return nil
+	}
+	fields := make([]zapcore.Field, 0, len(kvs))
+	var invalid []interface{}
+	for i := 0; i < len(kvs); i++ {
+		switch x := kvs[i].(type) {
+		case zapcore.Field:
+			fields = append(fields, x)
+			continue
+		case iface.Field:
+			fields = append(fields, zapField(x))
+			continue
+		}
+		if i+1 >= len(kvs) {
+			logger.DPanic("Ignored key without a value.", zap.Any("ignored", kvs[i]))
+			break
+		}
+		key, ok := kvs[i].(string)
+		if !ok {
+			invalid = append(invalid, kvs[i], kvs[i+1])
+		} else if valuer, ok := kvs[i+1].(iface.Valuer); ok {
+			fields = append(fields, valuerField(key, valuer))
+		} else {
+			fields = append(fields, zap.Any(key, kvs[i+1]))
+		}
+		i++
+	}
+	if len(invalid) > 0 {
+		logger.DPanic("Ignored key-value pairs with non-string keys.", zap.Any("invalid", invalid))
+	}
+	return fields
+}
+
+// isLazy 判断 iface.Field 的值是否为 iface.Valuer
+func isLazy(f iface.Field) bool {
+	_, ok := f.Interface.(iface.Valuer)
+	return ok && f.Type == iface.AnyType
+}
+
+// splitLazy 将 args 拆分为可以预先编码的字段及需要每次输出日志时计算的字段,
+// 没有需要计算的字段时 plain 为 args
+func splitLazy(args []interface{}) (plain, lazy []interface{}) {
+	if !hasLazy(args) {
+		return args, nil
+	}
+	for i := 0; i < len(args); i++ {
+		switch x := args[i].(type) {
+		case zapcore.Field:
+			plain = append(plain, x)
+			continue
+		case iface.Field:
+			if isLazy(x) {
+				lazy = append(lazy, x)
+			} else {
+				plain = append(plain, x)
+			}
+			continue
+		}
+		if i+1 >= len(args) {
+			plain = append(plain, args[i])
+			continue
+		}
+		if _, ok := args[i+1].(iface.Valuer); ok {
+			lazy = append(lazy, args[i], args[i+1])
+		} else {
+			plain = append(plain, args[i], args[i+1])
+		}
+		i++
+	}
+	return plain, lazy
+}
+
+// hasLazy 判断 args 中是否有需要每次输出日志时计算的字段
+func hasLazy(args []interface{}) bool {
+	for i := 0; i < len(args); i++ {
+		switch x := args[i].(type) {
+		case zapcore.Field:
+			continue
+		case iface.Field:
+			if isLazy(x) {
+				return true
+			}
+			continue
+		}
+		if i+1 < len(args) {
+			if _, ok := args[i+1].(iface.Valuer); ok {
+				return true
+			}
+		}
+		i++
+	}
+	return false
+}
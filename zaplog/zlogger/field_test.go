@@ -0,0 +1,65 @@
+package zlogger
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+type tObject struct {
+	name string
+	age  int64
+}
+
+func (p tObject) MarshalLogObject(enc iface.ObjectEncoder) error {
+	enc.AddString("name", p.name)
+	enc.AddInt64("age", p.age)
+	return nil
+}
+
+func TestZapField(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	err := errors.New("EOF")
+	tests := []struct {
+		f    iface.Field
+		want zapcore.Field
+	}{
+		{f: iface.Field{Type: iface.SkipType}, want: zap.Skip()},
+		{f: iface.Field{Key: "k", Type: iface.StringType, String: "v"}, want: zap.String("k", "v")},
+		{f: iface.Field{Key: "k", Type: iface.Int64Type, Integer: -1}, want: zap.Int64("k", -1)},
+		{f: iface.Field{Key: "k", Type: iface.Uint64Type, Integer: -1}, want: zap.Uint64("k", math.MaxUint64)},
+		{f: iface.Field{Key: "k", Type: iface.Float64Type, Integer: int64(math.Float64bits(1.5))}, want: zap.Float64("k", 1.5)},
+		{f: iface.Field{Key: "k", Type: iface.BoolType, Integer: 1}, want: zap.Bool("k", true)},
+		{f: iface.Field{Key: "k", Type: iface.DurationType, Integer: int64(time.Second)}, want: zap.Duration("k", time.Second)},
+		{f: iface.Field{Key: "k", Type: iface.TimeType, Integer: now.UnixNano(), Interface: time.UTC}, want: zap.Time("k", now)},
+		{f: iface.Field{Key: "k", Type: iface.ErrorType, Interface: err}, want: zap.NamedError("k", err)},
+		{f: iface.Field{Key: "k", Type: iface.ObjectType, Interface: tObject{}}, want: zap.Object("k", objectMarshaler{m: tObject{}})},
+		{f: iface.Field{Key: "k", Type: iface.AnyType, Interface: tObject{}}, want: zap.Object("k", objectMarshaler{m: tObject{}})},
+		{f: iface.Field{Key: "k", Type: iface.AnyType, Interface: []int{1}}, want: zap.Ints("k", []int{1})},
+	}
+	for i, tt := range tests {
+		assert.Equal(t, tt.want, zapField(tt.f), "%d: unexpected zap field", i)
+	}
+}
+
+func TestLoggerField(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newJSONTestLogger(iface.DEBUG, &buf, 1)
+
+	logger.WithArgs(iface.Field{Key: "k1", Type: iface.StringType, String: "v1"}).Infow("field",
+		iface.Field{Key: "k2", Type: iface.Int64Type, Integer: 2}, "k3", 3,
+		iface.Field{Key: "user", Type: iface.ObjectType, Interface: tObject{name: "tom", age: 18}},
+		iface.Field{Type: iface.SkipType})
+
+	want := `{"msg":"field","k1":"v1","k2":2,"k3":3,"user":{"name":"tom","age":18}}
+`
+	assert.Equal(t, want, buf.String(), "unexpected output")
+}
@@ -3,6 +3,8 @@ package zlogger
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -15,44 +17,81 @@ type ContextHook interface {
 	WithContext(ctx context.Context) (args []interface{})
 }
 
+// CoreHook 由需要访问日志条目的 ContextHook 实现, 如将日志添加为 span 事件;
+// WithContext 时使用 WrapCore 包装日志对象的 core
+type CoreHook interface {
+	WrapCore(ctx context.Context, core zapcore.Core) zapcore.Core
+}
+
+// AsCoreHook 返回 hook 实现的 CoreHook; hook 同时实现了 WrapsCore 方法且返回 false 时,
+// 视为不包装 core 的普通 ContextHook, 如组合了多个 ContextHook 且其中没有 CoreHook 时
+func AsCoreHook(hook ContextHook) (CoreHook, bool) {
+	h, ok := hook.(CoreHook)
+	if !ok {
+		return nil, false
+	}
+	if w, ok := hook.(interface{ WrapsCore() bool }); ok && !w.WrapsCore() {
+		return nil, false
+	}
+	return h, true
+}
+
+// ExitReturned 由 core 在 FATAL 级别日志的退出函数返回时(如测试中拦截了退出) panic,
+// Logger 恢复该 panic 使 Fatal 等方法正常返回, 避免 zap 调用 os.Exit
+type ExitReturned struct{}
+
+func recoverExitReturned() {
+	if r := recover(); r != nil {
+		if _, ok := r.(ExitReturned); !ok {
+			panic(r)
+		}
+	}
+}
+
+// Logger 在 Named, WithArgs 及 WithContext 时将绑定的字段预先编码到 core 中,
+// 并缓存各个调用深度的日志对象, 输出日志时不再复制日志对象及重复编码字段
 type Logger struct {
-	base *zap.Logger
-	hook ContextHook
+	root    *zap.Logger // 未绑定字段的日志对象
+	base    *zap.Logger // 绑定了 ctxs 及 args 的日志对象
+	hook    ContextHook
+	callers *callers
 
 	ctxs []interface{}
 	args []interface{}
+	lazy []interface{} // 值为 iface.Valuer 的字段, 每次输出日志时计算
 }
 
 func New(name string, core zapcore.Core, hook ContextHook, opts ...zap.Option) *Logger {
 	base := zap.New(core, opts...).Named(name)
 	return &Logger{
-		base: base,
-		hook: hook,
+		root:    base,
+		base:    base,
+		hook:    hook,
+		callers: newCallers(base),
 	}
 }
 
-func (p *Logger) clone(nctxs, nargs int) *Logger {
-	c := &Logger{
-		base: p.base,
-		hook: p.hook,
-	}
-	if n := len(p.ctxs); n > 0 {
-		c.ctxs = make([]interface{}, n, n+nctxs)
-		copy(c.ctxs, p.ctxs)
-	}
-	if n := len(p.args); n > 0 {
-		c.args = make([]interface{}, n, n+nargs)
-		copy(c.args, p.args)
+func (p *Logger) clone() *Logger {
+	c := *p
+	return &c
+}
+
+// with 返回 base 绑定 args 后的日志对象
+func with(base *zap.Logger, args []interface{}) *zap.Logger {
+	if len(args) <= 0 {
+		return base
 	}
-	return c
+	return base.Sugar().With(zapArgs(args)...).Desugar()
 }
 
 func (p *Logger) Named(name string) iface.Logger {
 	if len(name) <= 0 {
 		return p
 	}
-	c := p.clone(0, 0)
+	c := p.clone()
+	c.root = c.root.Named(name)
 	c.base = c.base.Named(name)
+	c.callers = newCallers(c.base)
 	return c
 }
 
@@ -60,24 +99,63 @@ func (p *Logger) WithArgs(args ...interface{}) iface.Logger {
 	if len(args) <= 0 {
 		return p
 	}
-	c := p.clone(0, len(args))
-	c.args = append(c.args, args...)
+	plain, lazy := splitLazy(args)
+	c := p.clone()
+	c.args = append(c.args[:len(c.args):len(c.args)], plain...)
+	c.lazy = append(c.lazy[:len(c.lazy):len(c.lazy)], lazy...)
+	c.base = with(c.base, plain)
+	c.callers = newCallers(c.base)
 	return c
 }
 
 func (p *Logger) WithContext(ctx context.Context) iface.Logger {
-	if p.hook == nil {
-		return p
+	return p.withContext(ctx)
+}
+
+// contextArgs 返回 ContextHook 及 ctx 携带的日志字段
+func (p *Logger) contextArgs(ctx context.Context) (args []interface{}) {
+	if p.hook != nil {
+		args = p.hook.WithContext(ctx)
 	}
-	args := p.hook.WithContext(ctx)
-	if len(args) <= 0 {
+	if kvs := iface.ContextArgs(ctx); len(kvs) > 0 {
+		args = append(args[:len(args):len(args)], kvs...)
+	}
+	return args
+}
+
+func (p *Logger) withContext(ctx context.Context) *Logger {
+	return p.bindContext(ctx, p.contextArgs(ctx))
+}
+
+// bindContext 返回绑定了 ctx 的字段 args 的日志对象
+func (p *Logger) bindContext(ctx context.Context, args []interface{}) *Logger {
+	h, ok := AsCoreHook(p.hook)
+	if len(args) <= 0 && !ok {
 		return p
 	}
-	c := p.clone(len(args), 0)
-	c.ctxs = append(c.ctxs, args...)
+	plain, lazy := splitLazy(args)
+	c := p.clone()
+	c.ctxs = append(c.ctxs[:len(c.ctxs):len(c.ctxs)], plain...)
+	c.lazy = append(c.lazy[:len(c.lazy):len(c.lazy)], lazy...)
+	if ok {
+		c.root = c.root.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return h.WrapCore(ctx, core)
+		}))
+	}
+	if ok || len(c.args) > 0 {
+		// ctx 中的字段在 args 之前输出, 需要重新绑定
+		c.base = with(with(c.root, c.ctxs), c.args)
+	} else {
+		c.base = with(c.base, plain)
+	}
+	c.callers = newCallers(c.base)
 	return c
 }
 
+func (p *Logger) Enabled(level iface.Level) bool {
+	return p.base.Core().Enabled(zbase.ZapLevel(level))
+}
+
 func (p *Logger) Sync() error {
 	return p.base.Sync()
 }
@@ -154,6 +232,30 @@ func (p *Logger) Fatalw(message string, kvs ...interface{}) {
 	p.Printw(1, iface.FATAL, message, kvs...)
 }
 
+func (p *Logger) DebugContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.DEBUG, message, kvs...)
+}
+
+func (p *Logger) InfoContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.INFO, message, kvs...)
+}
+
+func (p *Logger) WarnContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.WARN, message, kvs...)
+}
+
+func (p *Logger) ErrorContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.ERROR, message, kvs...)
+}
+
+func (p *Logger) PanicContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.PANIC, message, kvs...)
+}
+
+func (p *Logger) FatalContext(ctx context.Context, message string, kvs ...interface{}) {
+	p.Printc(ctx, 1, iface.FATAL, message, kvs...)
+}
+
 func (p *Logger) Print(depth int, level iface.Level, args ...interface{}) {
 	p.log(depth, zbase.ZapLevel(level), "", args, nil)
 }
@@ -166,7 +268,91 @@ func (p *Logger) Printw(depth int, level iface.Level, message string, kvs ...int
 	p.log(depth, zbase.ZapLevel(level), message, nil, kvs)
 }
 
+func (p *Logger) Printc(ctx context.Context, depth int, level iface.Level, message string, kvs ...interface{}) {
+	// 日志级别未开启时, 不执行 ContextHook
+	lvl := zbase.ZapLevel(level)
+	if lvl < zapcore.DPanicLevel && !p.base.Core().Enabled(lvl) {
+		return
+	}
+	l, kvs := p.contextLogger(ctx, kvs)
+	l.log(depth, lvl, message, nil, kvs)
+}
+
+// contextLogger 返回输出 ctx 日志的日志对象及字段, 字段顺序与 WithContext 相同,
+// ctx 中的字段在 WithArgs 绑定的字段之前输出; 没有绑定的字段时不创建子日志对象, ctx 中的字段添加到 kvs 之前
+func (p *Logger) contextLogger(ctx context.Context, kvs []interface{}) (*Logger, []interface{}) {
+	args := p.contextArgs(ctx)
+	if _, ok := AsCoreHook(p.hook); ok || (len(args) > 0 && len(p.args) > 0) {
+		return p.bindContext(ctx, args), kvs
+	}
+	if len(args) > 0 {
+		kvs = append(args[:len(args):len(args)], kvs...)
+	}
+	return p, kvs
+}
+
+// PrintEntry 与 Printc 相同, 但使用指定的时间及调用位置输出日志;
+// t 为零值时使用当前时间, pc 为 0 时不输出调用位置
+func (p *Logger) PrintEntry(ctx context.Context, t time.Time, pc uintptr, level iface.Level, message string, kvs ...interface{}) {
+	lvl := zbase.ZapLevel(level)
+	if lvl < zapcore.DPanicLevel && !p.base.Core().Enabled(lvl) {
+		return
+	}
+	l, kvs := p.contextLogger(ctx, kvs)
+	l.logEntry(t, pc, lvl, message, kvs)
+}
+
+func (p *Logger) logEntry(t time.Time, pc uintptr, lvl zapcore.Level, msg string, kvs []interface{}) {
+	lvl = clampLevel(lvl)
+	if lvl >= zapcore.FatalLevel {
+		defer recoverExitReturned()
+	}
+
+	ce := p.base.Check(lvl, msg)
+	if ce == nil {
+		return
+	}
+	if !t.IsZero() {
+		ce.Entry.Time = t
+	}
+	// 未开启 AddCaller 时不输出调用位置
+	if ce.Entry.Caller.Defined {
+		ce.Entry.Caller = entryCaller(pc)
+	}
+	ce.Write(p.fields(p.base, kvs)...)
+}
+
+// fields 返回输出日志时的字段, 包括每次输出日志时计算的字段; 只在日志确实输出时调用, 此时才计算 iface.Valuer
+func (p *Logger) fields(logger *zap.Logger, kvs []interface{}) []zapcore.Field {
+	if len(p.lazy) > 0 {
+		kvs = append(p.lazy[:len(p.lazy):len(p.lazy)], kvs...)
+	}
+	return zapFields(logger, kvs)
+}
+
+// clampLevel 将超出范围的级别限制为 DEBUG 至 FATAL
+func clampLevel(lvl zapcore.Level) zapcore.Level {
+	if lvl < zapcore.DebugLevel {
+		return zapcore.DebugLevel
+	}
+	if lvl > zapcore.FatalLevel {
+		return zapcore.FatalLevel
+	}
+	return lvl
+}
+
+// entryCaller 返回 pc 对应的调用位置
+func entryCaller(pc uintptr) zapcore.EntryCaller {
+	if pc == 0 {
+		return zapcore.EntryCaller{}
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, frame.File != "")
+}
+
 func (p *Logger) log(depth int, lvl zapcore.Level, template string, args []interface{}, kvs []interface{}) {
+	lvl = clampLevel(lvl)
+
 	// If logging at this level is completely disabled, skip the overhead of
 	// string formatting.
 	if lvl < zapcore.DPanicLevel && !p.base.Core().Enabled(lvl) {
@@ -174,6 +360,7 @@ func (p *Logger) log(depth int, lvl zapcore.Level, template string, args []inter
 	}
 
 	// Format with Sprint, Sprintf, or neither.
+	args = resolveArgs(args)
 	msg := template
 	if msg == "" && len(args) > 0 {
 		msg = fmt.Sprint(args...)
@@ -181,30 +368,14 @@ func (p *Logger) log(depth int, lvl zapcore.Level, template string, args []inter
 		msg = fmt.Sprintf(template, args...)
 	}
 
+	if lvl >= zapcore.FatalLevel {
+		defer recoverExitReturned()
+	}
+
 	// Output log message.
 	const skip = 2
-	base := p.base.WithOptions(zap.AddCallerSkip(skip + depth))
-	sugar := base.Sugar().With(p.ctxs...).With(p.args...)
-	switch lvl {
-	case zapcore.DebugLevel:
-		sugar.Debugw(msg, kvs...)
-	case zapcore.InfoLevel:
-		sugar.Infow(msg, kvs...)
-	case zapcore.WarnLevel:
-		sugar.Warnw(msg, kvs...)
-	case zapcore.ErrorLevel:
-		sugar.Errorw(msg, kvs...)
-	case zapcore.DPanicLevel:
-		sugar.DPanicw(msg, kvs...)
-	case zapcore.PanicLevel:
-		sugar.Panicw(msg, kvs...)
-	case zapcore.FatalLevel:
-		sugar.Fatalw(msg, kvs...)
-	default:
-		if lvl > zapcore.FatalLevel {
-			sugar.Fatalw(msg, kvs...)
-		} else {
-			sugar.Debugw(msg, kvs...)
-		}
+	logger := p.callers.logger(skip + depth)
+	if ce := logger.Check(lvl, msg); ce != nil {
+		ce.Write(p.fields(logger, kvs)...)
 	}
 }
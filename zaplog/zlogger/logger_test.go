@@ -3,7 +3,10 @@ package zlogger
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -187,6 +190,151 @@ func TestLoggerWithContext(t *testing.T) {
 	assert.Equal(t, logged.entries, logs.AllUntimed(), "unexpected log entries")
 }
 
+func TestLoggerWithContextArgs(t *testing.T) {
+	hook := TContextHook{trace: true}
+
+	var logged TLogged
+	logger, logs := NewTestLogger(t, "", iface.DEBUG, nil)
+	hooked, hookedLogs := NewTestLogger(t, "", iface.DEBUG, &hook)
+
+	ctx := iface.ContextWithArgs(context.Background(), "request", "r1")
+	ctx = iface.ContextWithArgs(ctx, "user", "u1")
+	logger.WithContext(ctx).Info()
+	hooked.WithContext(ctx).Info()
+
+	logged.Add(zapcore.Entry{Level: zapcore.InfoLevel}, zap.String("request", "r1"), zap.String("user", "u1"))
+	assert.Equal(t, logged.entries, logs.AllUntimed(), "unexpected log entries")
+
+	logged = TLogged{}
+	logged.Add(zapcore.Entry{Level: zapcore.InfoLevel}, zap.String("trace_id", "123456"), zap.String("request", "r1"), zap.String("user", "u1"))
+	assert.Equal(t, logged.entries, hookedLogs.AllUntimed(), "unexpected hooked log entries")
+}
+
+type TCoreHook struct {
+	TContextHook
+	ctxs []context.Context
+}
+
+func (p *TCoreHook) WrapCore(ctx context.Context, core zapcore.Core) zapcore.Core {
+	p.ctxs = append(p.ctxs, ctx)
+	return core.With([]zapcore.Field{zap.Bool("wrapped", true)})
+}
+
+func TestLoggerWithCoreHook(t *testing.T) {
+	hook := TCoreHook{}
+
+	var logged TLogged
+	logger, logs := NewTestLogger(t, "", iface.DEBUG, &hook)
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, 1)
+	logger.WithContext(ctx).Info()
+	hook.trace = true
+	logger.WithContext(ctx).WithArgs("k1", "v1").Info()
+
+	logged.Add(zapcore.Entry{Level: zapcore.InfoLevel}, zap.Bool("wrapped", true))
+	logged.Add(zapcore.Entry{Level: zapcore.InfoLevel}, zap.Bool("wrapped", true), zap.String("trace_id", "123456"), zap.String("k1", "v1"))
+
+	assert.Equal(t, logged.entries, logs.AllUntimed(), "unexpected log entries")
+	assert.Equal(t, []context.Context{ctx, ctx}, hook.ctxs, "unexpected wrap contexts")
+}
+
+type TCountHook struct {
+	TContextHook
+	count int
+}
+
+func (p *TCountHook) WithContext(ctx context.Context) []interface{} {
+	p.count++
+	return p.TContextHook.WithContext(ctx)
+}
+
+func TestLoggerPrintc(t *testing.T) {
+	hook := TCountHook{TContextHook: TContextHook{trace: true}}
+
+	var logged TLogged
+	logger, logs := NewTestLogger(t, "", iface.INFO, &hook)
+
+	ctx := iface.ContextWithArgs(context.Background(), "request", "r1")
+	logger.DebugContext(ctx, "debug", "k1", "v1")
+	logger.Printc(ctx, 0, iface.DEBUG, "debug", "k1", "v1")
+	assert.Equal(t, 0, hook.count, "context hook called for disabled level")
+
+	logger.InfoContext(ctx, "info", "k1", "v1")
+	logger.WarnContext(ctx, "warn")
+	logger.ErrorContext(ctx, "error")
+	logger.Printc(ctx, 0, iface.INFO, "printc", "k2", "v2")
+	assert.Panics(t, func() { logger.PanicContext(ctx, "panic") }, "expected panic")
+	assert.Equal(t, 5, hook.count, "unexpected context hook calls")
+
+	fields := []zapcore.Field{zap.String("trace_id", "123456"), zap.String("request", "r1")}
+	logged.Add(zapcore.Entry{Level: zapcore.InfoLevel, Message: "info"}, append(fields, zap.String("k1", "v1"))...)
+	logged.Add(zapcore.Entry{Level: zapcore.WarnLevel, Message: "warn"}, fields...)
+	logged.Add(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "error"}, fields...)
+	logged.Add(zapcore.Entry{Level: zapcore.InfoLevel, Message: "printc"}, append(fields, zap.String("k2", "v2"))...)
+	logged.Add(zapcore.Entry{Level: zapcore.PanicLevel, Message: "panic"}, fields...)
+	assert.Equal(t, logged.entries, logs.AllUntimed(), "unexpected log entries")
+}
+
+func TestLoggerPrintcOrder(t *testing.T) {
+	hook := TCountHook{TContextHook: TContextHook{trace: true}}
+	logger, logs := NewTestLogger(t, "", iface.DEBUG, &hook)
+	child := logger.WithArgs("k0", "v0")
+
+	// Printc 与 WithContext(ctx).Printw 的字段顺序相同, ctx 中的字段在绑定的字段之前
+	ctx := iface.ContextWithArgs(context.Background(), "request", "r1")
+	child.WithContext(ctx).Infow("info", "k1", "v1")
+	child.InfoContext(ctx, "info", "k1", "v1")
+	assert.Equal(t, 2, hook.count, "unexpected context hook calls")
+
+	output := logs.AllUntimed()
+	assert.Equal(t, 2, len(output), "unexpected number of logs written out")
+	want := []zapcore.Field{zap.String("trace_id", "123456"), zap.String("request", "r1"), zap.String("k0", "v0"), zap.String("k1", "v1")}
+	for _, e := range output {
+		assert.Equal(t, want, e.Context, "unexpected fields")
+	}
+}
+
+func TestLoggerPrintcCaller(t *testing.T) {
+	logger, logs := NewTestLogger(t, "name", iface.DEBUG, nil, zap.AddCaller())
+
+	logger.InfoContext(context.Background(), "info")
+	output := logs.AllUntimed()
+	assert.Equal(t, 1, len(output), "unexpected number of logs written out")
+	assert.Regexp(t, `logger_test.go`, output[0].Caller.String(), "unexpected caller")
+}
+
+func TestLoggerPrintEntry(t *testing.T) {
+	var logged TLogged
+	logger, logs := NewTestLogger(t, "", iface.INFO, &TContextHook{trace: true}, zap.AddCaller())
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	pc, file, line, _ := runtime.Caller(0)
+	ctx := iface.ContextWithArgs(context.Background(), "request", "r1")
+	logger.PrintEntry(ctx, now, pc, iface.DEBUG, "debug")
+	logger.PrintEntry(ctx, now, pc, iface.INFO, "info", "k1", "v1", iface.Field{Key: "k2", Type: iface.StringType, String: "v2"})
+	logger.WithArgs("k0", "v0").(*Logger).PrintEntry(context.Background(), now, 0, iface.WARN, "warn")
+
+	// 调用位置的 PC 由 runtime.CallersFrames 调整, 只比较文件及行号
+	output := logs.All()
+	for i := range output {
+		output[i].Caller.PC = 0
+	}
+	caller := zapcore.NewEntryCaller(0, file, line, true)
+	logged.Add(zapcore.Entry{Level: zapcore.InfoLevel, Time: now, Caller: caller, Message: "info"},
+		zap.String("trace_id", "123456"), zap.String("request", "r1"), zap.String("k1", "v1"), zap.String("k2", "v2"))
+	logged.Add(zapcore.Entry{Level: zapcore.WarnLevel, Time: now, Message: "warn"},
+		zap.String("trace_id", "123456"), zap.String("k0", "v0"))
+	assert.Equal(t, logged.entries, output, "unexpected log entries")
+}
+
+func TestLoggerEnabled(t *testing.T) {
+	logger, _ := NewTestLogger(t, "", iface.WARN, nil)
+	assert.False(t, logger.Enabled(iface.INFO), "info enabled")
+	assert.True(t, logger.Enabled(iface.WARN), "warn disabled")
+	assert.True(t, logger.Enabled(iface.ERROR), "error disabled")
+}
+
 func TestLoggerName(t *testing.T) {
 	var logged TLogged
 	logger, logs := NewTestLogger(t, "name", iface.DEBUG, nil)
@@ -205,3 +353,34 @@ func TestLoggerAddCaller(t *testing.T) {
 	assert.Regexp(t, `logger_test.go`, output[0].Caller.String(), "unexpected caller")
 	//assert.Equal(t, 164, output[0].Caller.Line, "unexpected line")
 }
+
+func printHelper(logger *Logger, depth int) {
+	logger.Print(depth, iface.INFO, "helper")
+}
+
+func TestLoggerCallerDepth(t *testing.T) {
+	logger, logs := NewTestLogger(t, "", iface.DEBUG, nil, zap.AddCaller())
+	child := logger.WithArgs("k1", "v1").(*Logger)
+
+	for i := 0; i < 2; i++ {
+		printHelper(child, 0)
+		printHelper(child, 1)
+		child.Print(0, iface.INFO, "direct")
+	}
+
+	output := logs.AllUntimed()
+	assert.Equal(t, 6, len(output), "unexpected number of logs written out")
+	for i, e := range output {
+		switch i % 3 {
+		case 0:
+			assert.Equal(t, "zlogger.printHelper", funcName(e.Caller), "%d: unexpected caller", i)
+		default:
+			assert.Equal(t, "zlogger.TestLoggerCallerDepth", funcName(e.Caller), "%d: unexpected caller", i)
+		}
+	}
+}
+
+func funcName(c zapcore.EntryCaller) string {
+	name := runtime.FuncForPC(c.PC).Name()
+	return name[strings.LastIndexByte(name, '/')+1:]
+}
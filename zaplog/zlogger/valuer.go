@@ -0,0 +1,24 @@
+package zlogger
+
+import (
+	"github.com/ironzhang/tlog/iface"
+)
+
+// resolveArgs 计算格式化参数中的 iface.Valuer, 没有时返回 args
+func resolveArgs(args []interface{}) []interface{} {
+	var out []interface{}
+	for i, arg := range args {
+		if _, ok := arg.(iface.Valuer); !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]interface{}, len(args))
+			copy(out, args)
+		}
+		out[i] = iface.Resolve(arg)
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
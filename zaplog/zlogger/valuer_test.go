@@ -0,0 +1,127 @@
+package zlogger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ironzhang/tlog/iface"
+)
+
+type tCountValuer struct {
+	count int
+	value interface{}
+}
+
+func (p *tCountValuer) LogValue() interface{} {
+	p.count++
+	return p.value
+}
+
+func newJSONTestLogger(level iface.Level, buf *bytes.Buffer, ncores int) *Logger {
+	cfg := zapcore.EncoderConfig{MessageKey: "msg"}
+	cores := make([]zapcore.Core, 0, ncores)
+	for i := 0; i < ncores; i++ {
+		cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(cfg), zapcore.AddSync(buf), zapcore.Level(level)))
+	}
+	return New("", zapcore.NewTee(cores...), nil)
+}
+
+func TestLoggerValuer(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newJSONTestLogger(iface.INFO, &buf, 2)
+
+	v := &tCountValuer{value: map[string]interface{}{"a": "<b>"}}
+	logger.Debugw("debug", "dump", v)
+	assert.Equal(t, 0, v.count, "valuer resolved at disabled level")
+
+	logger.Infow("info", "dump", v, zap.Int("k1", 1), "k2", 2)
+	assert.Equal(t, 1, v.count, "valuer resolved more than once")
+
+	want := `{"msg":"info","dump":{"a":"<b>"},"k1":1,"k2":2}
+{"msg":"info","dump":{"a":"<b>"},"k1":1,"k2":2}
+`
+	assert.Equal(t, want, buf.String(), "unexpected output")
+}
+
+func TestLoggerWithArgsValuer(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newJSONTestLogger(iface.DEBUG, &buf, 1)
+
+	v := &tCountValuer{value: 1}
+	l := logger.WithArgs("v", v)
+	assert.Equal(t, 0, v.count, "valuer resolved at WithArgs")
+
+	l.Info("first")
+	v.value = 2
+	l.Info("second")
+	l.Infof("format %v", v)
+	assert.Equal(t, 4, v.count, "unexpected resolve count")
+
+	want := `{"msg":"first","v":1}
+{"msg":"second","v":2}
+{"msg":"format 2","v":2}
+`
+	assert.Equal(t, want, buf.String(), "unexpected output")
+}
+
+type tUser struct {
+	name string
+}
+
+func (u tUser) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", u.name)
+	return nil
+}
+
+func TestLoggerValuerEncoding(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := zapcore.EncoderConfig{MessageKey: "msg", EncodeDuration: zapcore.StringDurationEncoder, EncodeTime: zapcore.ISO8601TimeEncoder}
+	logger := New("", zapcore.NewCore(zapcore.NewJSONEncoder(cfg), zapcore.AddSync(&buf), zapcore.DebugLevel), nil)
+
+	// iface.Valuer 计算后的值与直接输出该值时的编码相同
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	values := []interface{}{5 * time.Second, errors.New("boom"), ts, tUser{name: "alice"}, map[string]int{"a": 1}}
+	for _, v := range values {
+		v := v
+		buf.Reset()
+		logger.Infow("eager", "v", v)
+		logger.Infow("eager", iface.Field{Key: "v", Type: iface.AnyType, Interface: v})
+		logger.Infow("eager", "v", &tCountValuer{value: v})
+		logger.Infow("eager", iface.Field{Key: "v", Type: iface.AnyType, Interface: &tCountValuer{value: v}})
+		logger.WithArgs("v", &tCountValuer{value: v}).Info("eager")
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		for i, line := range lines[1:] {
+			assert.Equal(t, lines[0], line, "%T: %d: unexpected output", v, i+1)
+		}
+	}
+
+	buf.Reset()
+	logger.Infow("lazy", "d", &tCountValuer{value: 5 * time.Second}, "err", &tCountValuer{value: errors.New("boom")}, "t", &tCountValuer{value: ts})
+	assert.Equal(t, `{"msg":"lazy","d":"5s","err":"boom","t":"2024-01-02T03:04:05.000Z"}`+"\n", buf.String(), "unexpected output")
+}
+
+func TestZapArgs(t *testing.T) {
+	v := &tCountValuer{value: time.Second}
+	kvs := []interface{}{"k1", 1, zap.String("k2", "v2"), "k3", v, 4, v, "dangling"}
+	got := zapArgs(kvs)
+	if got, want := len(got), len(kvs)-1; got != want {
+		t.Fatalf("len: got %d, want %d", got, want)
+	}
+	assert.Equal(t, kvs[:3], got[:3], "unexpected unchanged items")
+	assert.Equal(t, zap.Duration("k3", time.Second), got[3], "unexpected valuer field")
+	assert.Equal(t, []interface{}{4, v, "dangling"}, got[4:], "unexpected unchanged items")
+	assert.Equal(t, 1, v.count, "unexpected resolve count")
+
+	plain := []interface{}{"k1", 1, "k2"}
+	if got := zapArgs(plain); &got[0] != &plain[0] {
+		t.Errorf("lazy fields: unexpected copy")
+	}
+}
@@ -0,0 +1,115 @@
+package zotel
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+const eventName = "log"
+
+// eventCore 将日志添加为 span 事件
+type eventCore struct {
+	zapcore.LevelEnabler
+	span   trace.Span
+	fields []zapcore.Field
+}
+
+func (c *eventCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &eventCore{
+		LevelEnabler: c.LevelEnabler,
+		span:         c.span,
+		fields:       make([]zapcore.Field, 0, len(c.fields)+len(fields)),
+	}
+	clone.fields = append(clone.fields, c.fields...)
+	clone.fields = append(clone.fields, fields...)
+	return clone
+}
+
+func (c *eventCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *eventCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	attrs := []attribute.KeyValue{
+		attribute.String("log.severity", ent.Level.CapitalString()),
+		attribute.String("log.message", ent.Message),
+	}
+	if ent.LoggerName != "" {
+		attrs = append(attrs, attribute.String("log.logger", ent.LoggerName))
+	}
+	attrs = append(attrs, fieldAttributes(c.fields, fields)...)
+	c.span.AddEvent(eventName, trace.WithTimestamp(ent.Time), trace.WithAttributes(attrs...))
+	return nil
+}
+
+func (c *eventCore) Sync() error {
+	return nil
+}
+
+// fieldAttributes 将日志字段转换为按名称排序的 span 属性
+func fieldAttributes(fieldsList ...[]zapcore.Field) []attribute.KeyValue {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, fields := range fieldsList {
+		for i := range fields {
+			fields[i].AddTo(enc)
+		}
+	}
+	if len(enc.Fields) <= 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, toAttribute(k, enc.Fields[k]))
+	}
+	return attrs
+}
+
+func toAttribute(k string, v interface{}) attribute.KeyValue {
+	switch x := v.(type) {
+	case string:
+		return attribute.String(k, x)
+	case bool:
+		return attribute.Bool(k, x)
+	case int:
+		return attribute.Int(k, x)
+	case int8:
+		return attribute.Int64(k, int64(x))
+	case int16:
+		return attribute.Int64(k, int64(x))
+	case int32:
+		return attribute.Int64(k, int64(x))
+	case int64:
+		return attribute.Int64(k, x)
+	case uint8:
+		return attribute.Int64(k, int64(x))
+	case uint16:
+		return attribute.Int64(k, int64(x))
+	case uint32:
+		return attribute.Int64(k, int64(x))
+	case float32:
+		return attribute.Float64(k, float64(x))
+	case float64:
+		return attribute.Float64(k, x)
+	case time.Duration:
+		return attribute.String(k, x.String())
+	case time.Time:
+		return attribute.String(k, x.Format(time.RFC3339Nano))
+	default:
+		return attribute.String(k, fmt.Sprint(x))
+	}
+}
@@ -0,0 +1,26 @@
+package zotel_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ironzhang/tlog/zaplog"
+	"github.com/ironzhang/tlog/zaplog/zotel"
+)
+
+func ExampleNew() {
+	hook := zotel.New(zotel.SetTraceIDKey("traceID"), zotel.SetSpanEvents(true))
+	logger, err := zaplog.New(zaplog.NewDevelopmentConfig(), zaplog.SetContextHook(hook))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "new: %v", err)
+		return
+	}
+	defer logger.Close()
+
+	// ctx 通常由 OpenTelemetry 的 tracer.Start 返回
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.SpanContext{})
+	logger.WithContext(ctx).Info("hello, world")
+}
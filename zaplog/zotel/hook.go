@@ -0,0 +1,70 @@
+package zotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultTraceIDKey    = "trace_id"
+	defaultSpanIDKey     = "span_id"
+	defaultTraceFlagsKey = "trace_flags"
+)
+
+// Hook 是一个 zaplog.ContextHook, 从 context 中的 OpenTelemetry span 提取
+// trace_id, span_id 及 trace_flags 字段, 并可选地将日志添加为 span 事件
+type Hook struct {
+	traceIDKey     string
+	spanIDKey      string
+	traceFlagsKey  string
+	traceparentKey string
+	spanEvents     bool
+}
+
+func New(opts ...Option) *Hook {
+	h := &Hook{
+		traceIDKey:    defaultTraceIDKey,
+		spanIDKey:     defaultSpanIDKey,
+		traceFlagsKey: defaultTraceFlagsKey,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *Hook) WithContext(ctx context.Context) (args []interface{}) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	traceID, spanID, flags := sc.TraceID().String(), sc.SpanID().String(), sc.TraceFlags().String()
+	if h.traceIDKey != "" {
+		args = append(args, h.traceIDKey, traceID)
+	}
+	if h.spanIDKey != "" {
+		args = append(args, h.spanIDKey, spanID)
+	}
+	if h.traceFlagsKey != "" {
+		args = append(args, h.traceFlagsKey, flags)
+	}
+	if h.traceparentKey != "" {
+		args = append(args, h.traceparentKey, "00-"+traceID+"-"+spanID+"-"+flags)
+	}
+	return args
+}
+
+// WrapCore 在开启 span 事件且 span 正在记录时, 将日志同时写入 span 事件
+func (h *Hook) WrapCore(ctx context.Context, core zapcore.Core) zapcore.Core {
+	if !h.spanEvents {
+		return core
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return core
+	}
+	return zapcore.NewTee(core, &eventCore{LevelEnabler: core, span: span})
+}
@@ -0,0 +1,136 @@
+package zotel
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ironzhang/tlog/iface"
+	"github.com/ironzhang/tlog/zaplog/zlogger"
+)
+
+type tEvent struct {
+	name  string
+	attrs []attribute.KeyValue
+}
+
+// tSpan 是一个正在记录的 span, 记录添加的事件
+type tSpan struct {
+	trace.Span
+	sc     trace.SpanContext
+	events []tEvent
+}
+
+func (s *tSpan) IsRecording() bool {
+	return true
+}
+
+func (s *tSpan) SpanContext() trace.SpanContext {
+	return s.sc
+}
+
+func (s *tSpan) AddEvent(name string, opts ...trace.EventOption) {
+	cfg := trace.NewEventConfig(opts...)
+	s.events = append(s.events, tEvent{name: name, attrs: cfg.Attributes()})
+}
+
+func NewTestSpanContext(t testing.TB) trace.SpanContext {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	if err != nil {
+		t.Fatalf("trace id from hex: %v", err)
+	}
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	if err != nil {
+		t.Fatalf("span id from hex: %v", err)
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+func TestHookWithContext(t *testing.T) {
+	ctx := trace.ContextWithSpanContext(context.Background(), NewTestSpanContext(t))
+
+	tests := []struct {
+		ctx  context.Context
+		opts []Option
+		args []interface{}
+	}{
+		{
+			ctx:  context.Background(),
+			args: nil,
+		},
+		{
+			ctx:  ctx,
+			args: []interface{}{"trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "span_id", "00f067aa0ba902b7", "trace_flags", "01"},
+		},
+		{
+			ctx:  ctx,
+			opts: []Option{SetTraceIDKey("traceID"), SetSpanIDKey(""), SetTraceFlagsKey("")},
+			args: []interface{}{"traceID", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		},
+		{
+			ctx:  ctx,
+			opts: []Option{SetTraceIDKey(""), SetSpanIDKey(""), SetTraceFlagsKey(""), SetTraceparentKey("traceparent")},
+			args: []interface{}{"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		},
+	}
+	for i, tt := range tests {
+		args := New(tt.opts...).WithContext(tt.ctx)
+		if got, want := args, tt.args; !reflect.DeepEqual(got, want) {
+			t.Errorf("%d: args: got %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestHookSpanEvents(t *testing.T) {
+	span := &tSpan{sc: NewTestSpanContext(t)}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zlogger.New("app", core, New(SetSpanEvents(true)))
+	logger.WithArgs("k1", "v1").WithContext(ctx).Infow("hello", "n", 1)
+	logger.WithContext(ctx).Debug("disabled")
+
+	if got, want := logs.Len(), 1; got != want {
+		t.Fatalf("logs: got %d, want %d", got, want)
+	}
+	if got, want := len(span.events), 1; got != want {
+		t.Fatalf("events: got %d, want %d", got, want)
+	}
+	want := tEvent{
+		name: "log",
+		attrs: []attribute.KeyValue{
+			attribute.String("log.severity", "INFO"),
+			attribute.String("log.message", "hello"),
+			attribute.String("log.logger", "app"),
+			attribute.String("k1", "v1"),
+			attribute.Int("n", 1),
+			attribute.String("span_id", "00f067aa0ba902b7"),
+			attribute.String("trace_flags", "01"),
+			attribute.String("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736"),
+		},
+	}
+	if got := span.events[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("event: got %v, want %v", got, want)
+	}
+}
+
+func TestHookWithoutSpanEvents(t *testing.T) {
+	span := &tSpan{sc: NewTestSpanContext(t)}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	core, _ := observer.New(zapcore.InfoLevel)
+	zlogger.New("", core, New(), zap.AddCaller()).WithContext(ctx).Print(0, iface.INFO, "hello")
+	if got := len(span.events); got != 0 {
+		t.Errorf("events: got %d, want 0", got)
+	}
+}
@@ -0,0 +1,38 @@
+package zotel
+
+type Option func(*Hook)
+
+// SetTraceIDKey 设置 trace id 的字段名, 为空时不输出
+func SetTraceIDKey(key string) Option {
+	return func(h *Hook) {
+		h.traceIDKey = key
+	}
+}
+
+// SetSpanIDKey 设置 span id 的字段名, 为空时不输出
+func SetSpanIDKey(key string) Option {
+	return func(h *Hook) {
+		h.spanIDKey = key
+	}
+}
+
+// SetTraceFlagsKey 设置 trace flags 的字段名, 为空时不输出
+func SetTraceFlagsKey(key string) Option {
+	return func(h *Hook) {
+		h.traceFlagsKey = key
+	}
+}
+
+// SetTraceparentKey 设置 W3C traceparent 的字段名, 默认为空, 即不输出
+func SetTraceparentKey(key string) Option {
+	return func(h *Hook) {
+		h.traceparentKey = key
+	}
+}
+
+// SetSpanEvents 设置是否将日志添加为 span 事件
+func SetSpanEvents(enable bool) Option {
+	return func(h *Hook) {
+		h.spanEvents = enable
+	}
+}
@@ -0,0 +1,185 @@
+package zsink
+
+import (
+	"errors"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var errNoSinks = errors.New("no sinks")
+
+// Fanout 将日志写入所有输出
+func Fanout(sinks ...zap.Sink) zap.Sink {
+	return fanout(sinks)
+}
+
+type fanout []zap.Sink
+
+func (f fanout) Write(b []byte) (int, error) {
+	return f.write(b, func(s zap.Sink) (int, error) {
+		return s.Write(b)
+	})
+}
+
+func (f fanout) WriteEntry(ent zapcore.Entry, fields []zapcore.Field, b []byte) (int, error) {
+	return f.write(b, func(s zap.Sink) (int, error) {
+		return WriteEntry(s, ent, fields, b)
+	})
+}
+
+func (f fanout) write(b []byte, write func(zap.Sink) (int, error)) (int, error) {
+	var err error
+	for _, s := range f {
+		_, e := write(s)
+		err = multierr.Append(err, e)
+	}
+	return len(b), err
+}
+
+func (f fanout) Sync() (err error) {
+	for _, s := range f {
+		err = multierr.Append(err, s.Sync())
+	}
+	return err
+}
+
+func (f fanout) Close() (err error) {
+	for _, s := range f {
+		err = multierr.Append(err, s.Close())
+	}
+	return err
+}
+
+// Failover 按顺序尝试各个输出, 只有前一个输出出错时才写入下一个;
+// 每次写入都从第一个输出开始, 因此第一个输出恢复后会自动切回.
+// 异步输出(如 unix, kafka)的写入不会出错, 不适合作为 Failover 的前置输出.
+func Failover(sinks ...zap.Sink) zap.Sink {
+	return failover(sinks)
+}
+
+type failover []zap.Sink
+
+func (f failover) Write(b []byte) (int, error) {
+	return f.write(b, func(s zap.Sink) (int, error) {
+		return s.Write(b)
+	})
+}
+
+func (f failover) WriteEntry(ent zapcore.Entry, fields []zapcore.Field, b []byte) (int, error) {
+	return f.write(b, func(s zap.Sink) (int, error) {
+		return WriteEntry(s, ent, fields, b)
+	})
+}
+
+func (f failover) write(b []byte, write func(zap.Sink) (int, error)) (int, error) {
+	if len(f) <= 0 {
+		return 0, errNoSinks
+	}
+	var err error
+	for _, s := range f {
+		_, e := write(s)
+		if e == nil {
+			return len(b), nil
+		}
+		err = multierr.Append(err, e)
+	}
+	return 0, err
+}
+
+// Sync 同步所有输出, 只有全部失败时才返回错误
+func (f failover) Sync() error {
+	if len(f) <= 0 {
+		return errNoSinks
+	}
+	var err error
+	failed := 0
+	for _, s := range f {
+		if e := s.Sync(); e != nil {
+			err = multierr.Append(err, e)
+			failed++
+		}
+	}
+	if failed < len(f) {
+		return nil
+	}
+	return err
+}
+
+func (f failover) Close() (err error) {
+	for _, s := range f {
+		err = multierr.Append(err, s.Close())
+	}
+	return err
+}
+
+// RoundRobin 按权重轮流写入各个输出, 权重小于等于 0 或未指定时为 1
+func RoundRobin(sinks []zap.Sink, weights []int) zap.Sink {
+	r := &roundRobin{
+		sinks:   sinks,
+		weights: make([]int, len(sinks)),
+		current: make([]int, len(sinks)),
+	}
+	for i := range r.weights {
+		r.weights[i] = 1
+		if i < len(weights) && weights[i] > 0 {
+			r.weights[i] = weights[i]
+		}
+		r.total += r.weights[i]
+	}
+	return r
+}
+
+type roundRobin struct {
+	mu      sync.Mutex
+	sinks   []zap.Sink
+	weights []int
+	current []int
+	total   int
+}
+
+// next 使用平滑加权轮询选择下一个输出
+func (r *roundRobin) next() zap.Sink {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	best := 0
+	for i := range r.current {
+		r.current[i] += r.weights[i]
+		if r.current[i] > r.current[best] {
+			best = i
+		}
+	}
+	r.current[best] -= r.total
+	return r.sinks[best]
+}
+
+func (r *roundRobin) Write(b []byte) (int, error) {
+	if len(r.sinks) <= 0 {
+		return 0, errNoSinks
+	}
+	return r.next().Write(b)
+}
+
+func (r *roundRobin) WriteEntry(ent zapcore.Entry, fields []zapcore.Field, b []byte) (int, error) {
+	if len(r.sinks) <= 0 {
+		return 0, errNoSinks
+	}
+	return WriteEntry(r.next(), ent, fields, b)
+}
+
+func (r *roundRobin) Sync() (err error) {
+	for _, s := range r.sinks {
+		err = multierr.Append(err, s.Sync())
+	}
+	return err
+}
+
+func (r *roundRobin) Close() (err error) {
+	for _, s := range r.sinks {
+		err = multierr.Append(err, s.Close())
+	}
+	return err
+}
@@ -0,0 +1,148 @@
+package zsink
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type tSink struct {
+	err    error
+	writes []string
+	syncs  int
+	closes int
+}
+
+func (p *tSink) Write(b []byte) (int, error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+	p.writes = append(p.writes, string(b))
+	return len(b), nil
+}
+
+func (p *tSink) Sync() error {
+	p.syncs++
+	return p.err
+}
+
+func (p *tSink) Close() error {
+	p.closes++
+	return nil
+}
+
+func TestFanout(t *testing.T) {
+	a, b := &tSink{}, &tSink{err: errors.New("b failed")}
+	s := Fanout(a, b)
+
+	if _, err := s.Write([]byte("1")); err == nil {
+		t.Errorf("write: expected error")
+	}
+	if got, want := a.writes, []string{"1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("writes: got %v, want %v", got, want)
+	}
+	s.Sync()
+	s.Close()
+	if a.syncs != 1 || b.syncs != 1 || a.closes != 1 || b.closes != 1 {
+		t.Errorf("syncs and closes: got %d %d %d %d, want all 1", a.syncs, b.syncs, a.closes, b.closes)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	a, b, c := &tSink{}, &tSink{}, &tSink{}
+	s := Failover(a, b, c)
+
+	s.Write([]byte("1"))
+	a.err = errors.New("a failed")
+	s.Write([]byte("2"))
+	b.err = errors.New("b failed")
+	s.Write([]byte("3"))
+	if err := s.Sync(); err != nil {
+		t.Errorf("sync: %v", err)
+	}
+	a.err, b.err = nil, nil
+	s.Write([]byte("4"))
+
+	if got, want := a.writes, []string{"1", "4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("a writes: got %v, want %v", got, want)
+	}
+	if got, want := b.writes, []string{"2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("b writes: got %v, want %v", got, want)
+	}
+	if got, want := c.writes, []string{"3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("c writes: got %v, want %v", got, want)
+	}
+
+	c.err = errors.New("c failed")
+	a.err, b.err = c.err, c.err
+	if _, err := s.Write([]byte("5")); err == nil {
+		t.Errorf("write: expected error")
+	}
+	if err := s.Sync(); err == nil {
+		t.Errorf("sync: expected error")
+	}
+	s.Close()
+	if a.closes != 1 || b.closes != 1 || c.closes != 1 {
+		t.Errorf("closes: got %d %d %d, want all 1", a.closes, b.closes, c.closes)
+	}
+
+	if _, err := Failover().Write([]byte("1")); err != errNoSinks {
+		t.Errorf("write: got %v, want %v", err, errNoSinks)
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	a, b, c := &tSink{}, &tSink{}, &tSink{}
+	s := RoundRobin([]zap.Sink{a, b, c}, []int{3, 0})
+
+	for i := 0; i < 10; i++ {
+		s.Write([]byte("x"))
+	}
+	if got, want := len(a.writes), 6; got != want {
+		t.Errorf("a writes: got %d, want %d", got, want)
+	}
+	if got, want := len(b.writes), 2; got != want {
+		t.Errorf("b writes: got %d, want %d", got, want)
+	}
+	if got, want := len(c.writes), 2; got != want {
+		t.Errorf("c writes: got %d, want %d", got, want)
+	}
+	s.Sync()
+	s.Close()
+	if a.syncs != 1 || c.closes != 1 {
+		t.Errorf("syncs and closes: got %d %d, want 1 1", a.syncs, c.closes)
+	}
+}
+
+type tEntrySink struct {
+	tSink
+	levels []zapcore.Level
+}
+
+func (p *tEntrySink) WriteEntry(ent zapcore.Entry, fields []zapcore.Field, b []byte) (int, error) {
+	p.levels = append(p.levels, ent.Level)
+	return p.Write(b)
+}
+
+func TestComposeWriteEntry(t *testing.T) {
+	plain, entry := &tSink{}, &tEntrySink{}
+	sinks := []zap.Sink{
+		Fanout(plain, entry),
+		Failover(entry, plain),
+		RoundRobin([]zap.Sink{entry}, nil),
+	}
+	for i, s := range sinks {
+		if _, err := WriteEntry(s, zapcore.Entry{Level: zapcore.WarnLevel}, nil, []byte("x")); err != nil {
+			t.Errorf("%d: write entry: %v", i, err)
+		}
+	}
+	if got, want := len(plain.writes), 1; got != want {
+		t.Errorf("plain writes: got %d, want %d", got, want)
+	}
+	if got, want := entry.levels, []zapcore.Level{zapcore.WarnLevel, zapcore.WarnLevel, zapcore.WarnLevel}; !reflect.DeepEqual(got, want) {
+		t.Errorf("entry levels: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+const maxResponseSize = 64 * 1024 * 1024
+
+// broker 是到单个 kafka broker 的连接, 只由生产者的后台协程使用
+type broker struct {
+	addr          string
+	clientID      string
+	dialTimeout   time.Duration
+	timeout       time.Duration
+	conn          net.Conn
+	correlationID int32
+}
+
+func (b *broker) connect() error {
+	if b.conn != nil {
+		return nil
+	}
+	conn, err := net.DialTimeout("tcp", b.addr, b.dialTimeout)
+	if err != nil {
+		return err
+	}
+	b.conn = conn
+	return nil
+}
+
+func (b *broker) close() {
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+}
+
+// request 发送请求并读取响应, response 为 false 时不等待响应
+func (b *broker) request(apiKey, apiVersion int16, body func(e *encoder), response bool) (*decoder, error) {
+	resp, err := b.roundTrip(apiKey, apiVersion, body, response)
+	if err != nil {
+		b.close()
+		return nil, fmt.Errorf("broker %s: %w", b.addr, err)
+	}
+	return resp, nil
+}
+
+func (b *broker) roundTrip(apiKey, apiVersion int16, body func(e *encoder), response bool) (*decoder, error) {
+	if err := b.connect(); err != nil {
+		return nil, err
+	}
+
+	// 1. 发送请求
+	b.correlationID++
+	e := encoder{b: make([]byte, 4, 256)}
+	encodeRequestHeader(&e, apiKey, apiVersion, b.correlationID, b.clientID)
+	body(&e)
+	binary.BigEndian.PutUint32(e.b, uint32(len(e.b)-4))
+
+	b.conn.SetDeadline(time.Now().Add(b.timeout))
+	if _, err := b.conn.Write(e.b); err != nil {
+		return nil, err
+	}
+	if !response {
+		return nil, nil
+	}
+
+	// 2. 读取响应
+	var head [8]byte
+	if _, err := io.ReadFull(b.conn, head[:]); err != nil {
+		return nil, err
+	}
+	size := int32(binary.BigEndian.Uint32(head[:4]))
+	if size < 4 || size > maxResponseSize {
+		return nil, fmt.Errorf("invalid response size %d", size)
+	}
+	if id := int32(binary.BigEndian.Uint32(head[4:])); id != b.correlationID {
+		return nil, fmt.Errorf("correlation id mismatch: got %d, want %d", id, b.correlationID)
+	}
+	buf := make([]byte, size-4)
+	if _, err := io.ReadFull(b.conn, buf); err != nil {
+		return nil, err
+	}
+	return &decoder{b: buf}, nil
+}
+
+func (b *broker) metadata(topic string) (*metadataResponse, error) {
+	d, err := b.request(apiMetadata, metadataVersion, func(e *encoder) {
+		encodeMetadataRequest(e, []string{topic})
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMetadataResponse(d)
+}
+
+func (b *broker) produce(acks int16, timeout time.Duration, topic string, parts []partitionRecords) ([]partitionResult, error) {
+	d, err := b.request(apiProduce, produceVersion, func(e *encoder) {
+		encodeProduceRequest(e, acks, timeout, topic, parts)
+	}, acks != 0)
+	if err != nil || d == nil {
+		return nil, err
+	}
+	return decodeProduceResponse(d)
+}
@@ -0,0 +1,332 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tBroker 是一个只支持 Metadata v4 及 Produce v3 的 kafka broker
+type tBroker struct {
+	ln         net.Listener
+	partitions int
+	wg         sync.WaitGroup
+
+	mu      sync.Mutex
+	conns   []net.Conn
+	acks    []int16
+	records map[int32][]record
+}
+
+func StartTestBroker(t testing.TB, addr string, partitions int) *tBroker {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	b := &tBroker{
+		ln:         ln,
+		partitions: partitions,
+		records:    make(map[int32][]record),
+	}
+	b.wg.Add(1)
+	go b.serve()
+	return b
+}
+
+func (b *tBroker) Addr() string {
+	return b.ln.Addr().String()
+}
+
+func (b *tBroker) Close() {
+	b.ln.Close()
+	b.mu.Lock()
+	for _, c := range b.conns {
+		c.Close()
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+}
+
+func (b *tBroker) Records(partition int32) []record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]record(nil), b.records[partition]...)
+}
+
+func (b *tBroker) Values() (values []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := 0; i < b.partitions; i++ {
+		for _, r := range b.records[int32(i)] {
+			values = append(values, string(r.value))
+		}
+	}
+	return values
+}
+
+func (b *tBroker) Acks() []int16 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]int16(nil), b.acks...)
+}
+
+func (b *tBroker) serve() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.conns = append(b.conns, conn)
+		b.mu.Unlock()
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			defer conn.Close()
+			for b.handle(conn) == nil {
+			}
+		}()
+	}
+}
+
+func (b *tBroker) handle(conn net.Conn) error {
+	var head [4]byte
+	if _, err := io.ReadFull(conn, head[:]); err != nil {
+		return err
+	}
+	body := make([]byte, binary.BigEndian.Uint32(head[:]))
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return err
+	}
+
+	d := decoder{b: body}
+	apiKey := d.int16()
+	d.int16() // api_version
+	correlationID := d.int32()
+	d.string() // client_id
+
+	e := encoder{b: make([]byte, 4)}
+	e.int32(correlationID)
+	switch apiKey {
+	case apiMetadata:
+		b.metadata(&d, &e)
+	case apiProduce:
+		if !b.produce(&d, &e) {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported api key %d", apiKey)
+	}
+	if d.err != nil {
+		return d.err
+	}
+	binary.BigEndian.PutUint32(e.b, uint32(len(e.b)-4))
+	_, err := conn.Write(e.b)
+	return err
+}
+
+func (b *tBroker) metadata(d *decoder, e *encoder) {
+	topics := make([]string, d.arrayLen())
+	for i := range topics {
+		topics[i] = d.string()
+	}
+	d.bool() // allow_auto_topic_creation
+
+	host, port, _ := net.SplitHostPort(b.Addr())
+	nport, _ := strconv.Atoi(port)
+
+	e.int32(0) // throttle_time_ms
+	e.arrayLen(1)
+	e.int32(0)
+	e.string(host)
+	e.int32(int32(nport))
+	e.nullString() // rack
+	e.nullString() // cluster_id
+	e.int32(0)     // controller_id
+	e.arrayLen(len(topics))
+	for _, topic := range topics {
+		e.int16(0)
+		e.string(topic)
+		e.bool(false)
+		e.arrayLen(b.partitions)
+		for i := 0; i < b.partitions; i++ {
+			e.int16(0)
+			e.int32(int32(i))
+			e.int32(0)
+			e.arrayLen(1)
+			e.int32(0)
+			e.arrayLen(1)
+			e.int32(0)
+		}
+	}
+}
+
+func (b *tBroker) produce(d *decoder, e *encoder) bool {
+	d.string() // transactional_id
+	acks := d.int16()
+	d.int32() // timeout_ms
+
+	b.mu.Lock()
+	b.acks = append(b.acks, acks)
+	b.mu.Unlock()
+
+	topics := d.arrayLen()
+	e.arrayLen(topics)
+	for ; topics > 0; topics-- {
+		e.string(d.string())
+		n := d.arrayLen()
+		e.arrayLen(n)
+		for ; n > 0; n-- {
+			partition := d.int32()
+			records, err := decodeRecordBatch(d.bytes())
+			code := int16(0)
+			if err != nil {
+				code = 2 // CORRUPT_MESSAGE
+			} else {
+				b.mu.Lock()
+				b.records[partition] = append(b.records[partition], records...)
+				b.mu.Unlock()
+			}
+			e.int32(partition)
+			e.int16(code)
+			e.int64(0)  // base_offset
+			e.int64(-1) // log_append_time_ms
+		}
+	}
+	e.int32(0) // throttle_time_ms
+	return acks != 0
+}
+
+func decodeRecordBatch(batch []byte) ([]record, error) {
+	d := decoder{b: batch}
+	d.int64() // base_offset
+	length := d.int32()
+	if int(length) != len(d.b) {
+		return nil, fmt.Errorf("batch length: got %d, want %d", length, len(d.b))
+	}
+	d.int32() // partition_leader_epoch
+	if magic := d.int8(); magic != 2 {
+		return nil, fmt.Errorf("magic: got %d, want 2", magic)
+	}
+	crc := uint32(d.int32())
+	if sum := crc32.Checksum(d.b, crc32c); sum != crc {
+		return nil, fmt.Errorf("crc: got %x, want %x", sum, crc)
+	}
+	d.int16() // attributes
+	d.int32() // last_offset_delta
+	first := d.int64()
+	d.int64() // max_timestamp
+	d.int64() // producer_id
+	d.int16() // producer_epoch
+	d.int32() // base_sequence
+
+	records := make([]record, d.arrayLen())
+	for i := range records {
+		size := d.varint()
+		rd := decoder{b: d.next(int(size))}
+		rd.int8() // attributes
+		delta := rd.varint()
+		rd.varint() // offset_delta
+		records[i].key = rd.varbytes()
+		records[i].value = rd.varbytes()
+		rd.varint() // headers
+		records[i].time = time.Unix(0, (first+delta)*int64(time.Millisecond))
+		if rd.err != nil {
+			return nil, rd.err
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return records, nil
+}
+
+func TestBrokerMetadata(t *testing.T) {
+	tb := StartTestBroker(t, "127.0.0.1:0", 3)
+	defer tb.Close()
+
+	b := &broker{addr: tb.Addr(), clientID: "test", dialTimeout: time.Second, timeout: time.Second}
+	defer b.close()
+
+	resp, err := b.metadata("test")
+	if err != nil {
+		t.Fatalf("metadata: %v", err)
+	}
+	if got, want := len(resp.brokers), 1; got != want {
+		t.Fatalf("brokers: got %d, want %d", got, want)
+	}
+	if got, want := len(resp.topics), 1; got != want {
+		t.Fatalf("topics: got %d, want %d", got, want)
+	}
+	if got, want := resp.topics[0].name, "test"; got != want {
+		t.Errorf("topic: got %q, want %q", got, want)
+	}
+	if got, want := len(resp.topics[0].partitions), 3; got != want {
+		t.Errorf("partitions: got %d, want %d", got, want)
+	}
+}
+
+func TestBrokerProduce(t *testing.T) {
+	tb := StartTestBroker(t, "127.0.0.1:0", 1)
+	defer tb.Close()
+
+	b := &broker{addr: tb.Addr(), clientID: "test", dialTimeout: time.Second, timeout: time.Second}
+	defer b.close()
+
+	now := time.Now()
+	parts := []partitionRecords{
+		{
+			partition: 0,
+			records: []record{
+				{key: []byte("k1"), value: []byte("v1"), time: now},
+				{key: nil, value: []byte("v2"), time: now.Add(time.Second)},
+			},
+		},
+	}
+	results, err := b.produce(1, time.Second, "test", parts)
+	if err != nil {
+		t.Fatalf("produce: %v", err)
+	}
+	if got, want := len(results), 1; got != want {
+		t.Fatalf("results: got %d, want %d", got, want)
+	}
+	if got := results[0].errorCode; got != 0 {
+		t.Fatalf("error code: got %d, want 0", got)
+	}
+
+	records := tb.Records(0)
+	if got, want := len(records), 2; got != want {
+		t.Fatalf("records: got %d, want %d", got, want)
+	}
+	if got, want := string(records[0].key), "k1"; got != want {
+		t.Errorf("key: got %q, want %q", got, want)
+	}
+	if records[1].key != nil {
+		t.Errorf("key: got %q, want nil", records[1].key)
+	}
+	if got, want := millis(records[1].time), millis(now.Add(time.Second)); got != want {
+		t.Errorf("time: got %d, want %d", got, want)
+	}
+}
+
+func TestBrokerUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	b := &broker{addr: addr, dialTimeout: 100 * time.Millisecond, timeout: time.Second}
+	if _, err = b.metadata("test"); err == nil {
+		t.Fatalf("metadata: expected error")
+	}
+	t.Logf("metadata: %v", err)
+}
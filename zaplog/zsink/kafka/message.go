@@ -0,0 +1,202 @@
+package kafka
+
+import (
+	"fmt"
+	"hash/crc32"
+	"time"
+)
+
+// 使用的 API 及版本, Produce v3 起使用 RecordBatch v2 消息格式
+const (
+	apiProduce      int16 = 0
+	apiMetadata     int16 = 3
+	produceVersion  int16 = 3
+	metadataVersion int16 = 4
+)
+
+type record struct {
+	key   []byte
+	value []byte
+	time  time.Time
+}
+
+type brokerMeta struct {
+	nodeID int32
+	host   string
+	port   int32
+}
+
+type partitionMeta struct {
+	errorCode int16
+	id        int32
+	leader    int32
+}
+
+type topicMeta struct {
+	errorCode  int16
+	name       string
+	partitions []partitionMeta
+}
+
+type metadataResponse struct {
+	brokers []brokerMeta
+	topics  []topicMeta
+}
+
+type partitionRecords struct {
+	partition int32
+	records   []record
+}
+
+type partitionResult struct {
+	partition int32
+	errorCode int16
+}
+
+// kafkaError 表示 broker 返回的错误码
+type kafkaError int16
+
+func (e kafkaError) Error() string {
+	return fmt.Sprintf("kafka: error code %d", int16(e))
+}
+
+func encodeRequestHeader(e *encoder, apiKey, apiVersion int16, correlationID int32, clientID string) {
+	e.int16(apiKey)
+	e.int16(apiVersion)
+	e.int32(correlationID)
+	e.string(clientID)
+}
+
+func encodeMetadataRequest(e *encoder, topics []string) {
+	e.arrayLen(len(topics))
+	for _, topic := range topics {
+		e.string(topic)
+	}
+	e.bool(false) // allow_auto_topic_creation
+}
+
+func decodeMetadataResponse(d *decoder) (*metadataResponse, error) {
+	var resp metadataResponse
+
+	d.int32() // throttle_time_ms
+	resp.brokers = make([]brokerMeta, d.arrayLen())
+	for i := range resp.brokers {
+		b := &resp.brokers[i]
+		b.nodeID = d.int32()
+		b.host = d.string()
+		b.port = d.int32()
+		d.string() // rack
+	}
+	d.string() // cluster_id
+	d.int32()  // controller_id
+
+	resp.topics = make([]topicMeta, d.arrayLen())
+	for i := range resp.topics {
+		t := &resp.topics[i]
+		t.errorCode = d.int16()
+		t.name = d.string()
+		d.bool() // is_internal
+		t.partitions = make([]partitionMeta, d.arrayLen())
+		for j := range t.partitions {
+			p := &t.partitions[j]
+			p.errorCode = d.int16()
+			p.id = d.int32()
+			p.leader = d.int32()
+			for n := d.arrayLen(); n > 0; n-- {
+				d.int32() // replica_nodes
+			}
+			for n := d.arrayLen(); n > 0; n-- {
+				d.int32() // isr_nodes
+			}
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &resp, nil
+}
+
+func encodeProduceRequest(e *encoder, acks int16, timeout time.Duration, topic string, parts []partitionRecords) {
+	e.nullString() // transactional_id
+	e.int16(acks)
+	e.int32(int32(timeout / time.Millisecond))
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(len(parts))
+	for _, p := range parts {
+		e.int32(p.partition)
+		e.bytes(encodeRecordBatch(p.records))
+	}
+}
+
+func decodeProduceResponse(d *decoder) ([]partitionResult, error) {
+	var results []partitionResult
+	for n := d.arrayLen(); n > 0; n-- {
+		d.string() // topic
+		for m := d.arrayLen(); m > 0; m-- {
+			var r partitionResult
+			r.partition = d.int32()
+			r.errorCode = d.int16()
+			d.int64() // base_offset
+			d.int64() // log_append_time_ms
+			results = append(results, r)
+		}
+	}
+	d.int32() // throttle_time_ms
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	return results, nil
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// encodeRecordBatch 将消息编码为 RecordBatch v2 格式, 不压缩
+func encodeRecordBatch(records []record) []byte {
+	if len(records) == 0 {
+		return nil
+	}
+
+	first, last := millis(records[0].time), millis(records[0].time)
+	for _, r := range records {
+		if ts := millis(r.time); ts > last {
+			last = ts
+		}
+	}
+
+	// 1. crc 校验的部分, 从 attributes 到结尾
+	var body encoder
+	body.int16(0) // attributes
+	body.int32(int32(len(records) - 1))
+	body.int64(first)
+	body.int64(last)
+	body.int64(-1) // producer_id
+	body.int16(-1) // producer_epoch
+	body.int32(-1) // base_sequence
+	body.arrayLen(len(records))
+	for i, r := range records {
+		var rec encoder
+		rec.int8(0) // attributes
+		rec.varint(millis(r.time) - first)
+		rec.varint(int64(i))
+		rec.varbytes(r.key)
+		rec.varbytes(r.value)
+		rec.varint(0) // headers
+		body.varint(int64(len(rec.b)))
+		body.b = append(body.b, rec.b...)
+	}
+
+	// 2. 批次头部
+	var e encoder
+	e.int64(0)                              // base_offset
+	e.int32(int32(len(body.b) + 4 + 1 + 4)) // batch_length
+	e.int32(-1)                             // partition_leader_epoch
+	e.int8(2)                               // magic
+	e.int32(int32(crc32.Checksum(body.b, crc32c)))
+	e.b = append(e.b, body.b...)
+	return e.b
+}
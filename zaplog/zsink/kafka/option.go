@@ -0,0 +1,85 @@
+package kafka
+
+import "time"
+
+type Option func(*Producer)
+
+// SetAcks 设置 broker 的确认方式, 0 不等待确认, 1 等待 leader 确认, -1 等待所有副本确认
+func SetAcks(acks int16) Option {
+	return func(p *Producer) {
+		p.acks = acks
+	}
+}
+
+func SetBatchSize(n int) Option {
+	return func(p *Producer) {
+		p.batchSize = n
+	}
+}
+
+func SetLinger(d time.Duration) Option {
+	return func(p *Producer) {
+		p.linger = d
+	}
+}
+
+func SetBacklog(backlog int) Option {
+	return func(p *Producer) {
+		p.backlog = backlog
+	}
+}
+
+func SetClientID(id string) Option {
+	return func(p *Producer) {
+		p.clientID = id
+	}
+}
+
+// SetKeyField 设置用作消息 key 的 json 字段, 默认为 logger 字段
+func SetKeyField(field string) Option {
+	return func(p *Producer) {
+		p.keyField = field
+	}
+}
+
+func SetDialTimeout(d time.Duration) Option {
+	return func(p *Producer) {
+		p.dialTimeout = d
+	}
+}
+
+func SetTimeout(d time.Duration) Option {
+	return func(p *Producer) {
+		p.timeout = d
+	}
+}
+
+func SetRetryInterval(d time.Duration) Option {
+	return func(p *Producer) {
+		p.retryInterval = d
+	}
+}
+
+func SetSyncTimeout(d time.Duration) Option {
+	return func(p *Producer) {
+		p.syncTimeout = d
+	}
+}
+
+func SetSpoolDir(dir string) Option {
+	return func(p *Producer) {
+		p.spoolDir = dir
+	}
+}
+
+func SetSpoolMaxSize(maxSize int) Option {
+	return func(p *Producer) {
+		p.spoolMaxSize = maxSize
+	}
+}
+
+func SetSpoolMaxSeq(maxSeq int) Option {
+	return func(p *Producer) {
+		p.spoolMaxSeq = maxSeq
+	}
+}
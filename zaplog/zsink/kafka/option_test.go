@@ -0,0 +1,73 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOption(t *testing.T) {
+	tests := []struct {
+		opt Option
+		chk func(p *Producer) bool
+	}{
+		{
+			opt: SetAcks(-1),
+			chk: func(p *Producer) bool { return p.acks == -1 },
+		},
+		{
+			opt: SetBatchSize(10),
+			chk: func(p *Producer) bool { return p.batchSize == 10 },
+		},
+		{
+			opt: SetLinger(time.Second),
+			chk: func(p *Producer) bool { return p.linger == time.Second },
+		},
+		{
+			opt: SetBacklog(20),
+			chk: func(p *Producer) bool { return p.backlog == 20 },
+		},
+		{
+			opt: SetClientID("client"),
+			chk: func(p *Producer) bool { return p.clientID == "client" },
+		},
+		{
+			opt: SetKeyField("name"),
+			chk: func(p *Producer) bool { return p.keyField == "name" },
+		},
+		{
+			opt: SetDialTimeout(2 * time.Second),
+			chk: func(p *Producer) bool { return p.dialTimeout == 2*time.Second },
+		},
+		{
+			opt: SetTimeout(3 * time.Second),
+			chk: func(p *Producer) bool { return p.timeout == 3*time.Second },
+		},
+		{
+			opt: SetRetryInterval(4 * time.Second),
+			chk: func(p *Producer) bool { return p.retryInterval == 4*time.Second },
+		},
+		{
+			opt: SetSyncTimeout(5 * time.Second),
+			chk: func(p *Producer) bool { return p.syncTimeout == 5*time.Second },
+		},
+		{
+			opt: SetSpoolDir("spool"),
+			chk: func(p *Producer) bool { return p.spoolDir == "spool" },
+		},
+		{
+			opt: SetSpoolMaxSize(30),
+			chk: func(p *Producer) bool { return p.spoolMaxSize == 30 },
+		},
+		{
+			opt: SetSpoolMaxSeq(40),
+			chk: func(p *Producer) bool { return p.spoolMaxSeq == 40 },
+		},
+	}
+	for i, tt := range tests {
+		p := &Producer{}
+		tt.opt(p)
+		if !tt.chk(p) {
+			t.Errorf("%d: failed to check", i)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// murmur2 与 kafka java 客户端默认分区器使用的哈希算法一致
+func murmur2(data []byte) uint32 {
+	const (
+		seed uint32 = 0x9747b28c
+		m    uint32 = 0x5bd1e995
+		r           = 24
+	)
+
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := uint32(data[i]) | uint32(data[i+1])<<8 | uint32(data[i+2])<<16 | uint32(data[i+3])<<24
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return h
+}
+
+// partitioner 有 key 时按 key 的哈希选择分区, 没有 key 时轮询
+type partitioner struct {
+	next uint32
+}
+
+func (p *partitioner) partition(key []byte, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	if key == nil {
+		p.next++
+		return int(p.next % uint32(n))
+	}
+	return int((murmur2(key) & 0x7fffffff) % uint32(n))
+}
+
+// jsonField 读取 json 对象中顶层字符串字段的值, 用作消息的 key
+func jsonField(data []byte, field string) []byte {
+	if field == "" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		if key, _ := t.(string); key == field {
+			var s string
+			if err = dec.Decode(&s); err != nil {
+				return nil
+			}
+			return []byte(s)
+		}
+		var skip json.RawMessage
+		if err = dec.Decode(&skip); err != nil {
+			return nil
+		}
+	}
+	return nil
+}
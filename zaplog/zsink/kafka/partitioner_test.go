@@ -0,0 +1,68 @@
+package kafka
+
+import "testing"
+
+func TestMurmur2(t *testing.T) {
+	// 与 kafka java 客户端 Utils.murmur2 的测试用例一致
+	tests := []struct {
+		s string
+		h int32
+	}{
+		{s: "21", h: -973932308},
+		{s: "foobar", h: -790332482},
+		{s: "a-little-bit-long-string", h: -985981536},
+		{s: "a-little-bit-longer-string", h: -1486304829},
+		{s: "lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8", h: -58897971},
+		{s: "abc", h: 479470107},
+	}
+	for i, tt := range tests {
+		if got, want := int32(murmur2([]byte(tt.s))), tt.h; got != want {
+			t.Errorf("%d: murmur2(%q): got %d, want %d", i, tt.s, got, want)
+		}
+	}
+}
+
+func TestPartitioner(t *testing.T) {
+	var p partitioner
+	if got, want := p.partition([]byte("foobar"), 0), 0; got != want {
+		t.Errorf("no partitions: got %d, want %d", got, want)
+	}
+
+	key := []byte("access")
+	first := p.partition(key, 8)
+	for i := 0; i < 10; i++ {
+		if got := p.partition(key, 8); got != first {
+			t.Errorf("key partition: got %d, want %d", got, first)
+		}
+	}
+
+	seen := make(map[int]bool)
+	for i := 0; i < 8; i++ {
+		seen[p.partition(nil, 8)] = true
+	}
+	if got, want := len(seen), 8; got != want {
+		t.Errorf("round robin partitions: got %d, want %d", got, want)
+	}
+}
+
+func TestJSONField(t *testing.T) {
+	tests := []struct {
+		data  string
+		field string
+		value []byte
+	}{
+		{data: `{"level":"info","logger":"access","msg":"hello"}`, field: "logger", value: []byte("access")},
+		{data: `{"level":"info","obj":{"logger":"inner"},"logger":"outer"}`, field: "logger", value: []byte("outer")},
+		{data: `{"level":"info","msg":"hello"}`, field: "logger", value: nil},
+		{data: `{"logger":1}`, field: "logger", value: nil},
+		{data: `{"logger":"access"}`, field: "", value: nil},
+		{data: "INFO\thello, world", field: "logger", value: nil},
+		{data: `{"logger":`, field: "logger", value: nil},
+	}
+	for i, tt := range tests {
+		value := jsonField([]byte(tt.data), tt.field)
+		if got, want := string(value), string(tt.value); got != want || (value == nil) != (tt.value == nil) {
+			t.Errorf("%d: value: got %q, want %q", i, value, tt.value)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package kafka
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+var errTimeout = errors.New("i/o timeout")
+
+const (
+	defaultAcks          = 1
+	defaultBatchSize     = 500
+	defaultLinger        = 100 * time.Millisecond
+	defaultBacklog       = 8192
+	defaultClientID      = "tlog"
+	defaultKeyField      = "logger"
+	defaultDialTimeout   = 1 * time.Second
+	defaultTimeout       = 10 * time.Second
+	defaultRetryInterval = 3 * time.Second
+	defaultSyncTimeout   = 15 * time.Second
+	defaultSpoolMaxSize  = 64 * 1024 * 1024
+	defaultSpoolMaxSeq   = 16
+)
+
+type message struct {
+	value []byte
+	time  time.Time
+	flush chan struct{}
+}
+
+// Producer 是一个写入 kafka topic 的日志输出,
+// 写入操作只将数据放入有界队列, 由后台协程按批次发送;
+// broker 不可用时消息缓存到本地 rollfile 目录, 恢复后按顺序重放.
+type Producer struct {
+	mu      sync.Mutex
+	closed  bool
+	queue   chan message
+	done    chan struct{}
+	exited  chan struct{}
+	dropped int64
+
+	seeds      []*broker
+	brokers    map[int32]*broker
+	partitions []partitionMeta
+	part       partitioner
+	spool      *spool
+	failed     bool
+	failedAt   time.Time
+
+	topic         string
+	acks          int16
+	batchSize     int
+	linger        time.Duration
+	backlog       int
+	clientID      string
+	keyField      string
+	dialTimeout   time.Duration
+	timeout       time.Duration
+	retryInterval time.Duration
+	syncTimeout   time.Duration
+	spoolDir      string
+	spoolMaxSize  int
+	spoolMaxSeq   int
+}
+
+func Open(addrs []string, topic string, opts ...Option) (*Producer, error) {
+	if len(addrs) <= 0 {
+		return nil, &os.PathError{Op: "open", Path: topic, Err: errors.New("no broker addresses")}
+	}
+	if topic == "" {
+		return nil, &os.PathError{Op: "open", Path: topic, Err: errors.New("empty topic")}
+	}
+
+	p := &Producer{
+		brokers:       make(map[int32]*broker),
+		topic:         topic,
+		acks:          defaultAcks,
+		batchSize:     defaultBatchSize,
+		linger:        defaultLinger,
+		backlog:       defaultBacklog,
+		clientID:      defaultClientID,
+		keyField:      defaultKeyField,
+		dialTimeout:   defaultDialTimeout,
+		timeout:       defaultTimeout,
+		retryInterval: defaultRetryInterval,
+		syncTimeout:   defaultSyncTimeout,
+		spoolDir:      filepath.Join("log", "spool", topic),
+		spoolMaxSize:  defaultSpoolMaxSize,
+		spoolMaxSeq:   defaultSpoolMaxSeq,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	switch p.acks {
+	case 0, 1, -1:
+	default:
+		return nil, &os.PathError{Op: "open", Path: topic, Err: fmt.Errorf("invalid acks %d", p.acks)}
+	}
+	if p.batchSize <= 0 {
+		p.batchSize = defaultBatchSize
+	}
+	if p.linger <= 0 {
+		p.linger = defaultLinger
+	}
+	if p.backlog <= 0 {
+		p.backlog = defaultBacklog
+	}
+	if p.spoolMaxSeq < 2 {
+		p.spoolMaxSeq = 2
+	}
+
+	for _, addr := range addrs {
+		p.seeds = append(p.seeds, p.newBroker(addr))
+	}
+	p.spool = openSpool(p.spoolDir, p.spoolMaxSize, p.spoolMaxSeq)
+
+	p.queue = make(chan message, p.backlog)
+	p.done = make(chan struct{})
+	p.exited = make(chan struct{})
+	go p.running()
+
+	return p, nil
+}
+
+func (p *Producer) newBroker(addr string) *broker {
+	return &broker{
+		addr:        addr,
+		clientID:    p.clientID,
+		dialTimeout: p.dialTimeout,
+		timeout:     p.timeout,
+	}
+}
+
+func (p *Producer) running() {
+	defer close(p.exited)
+	defer p.shutdown()
+
+	t := time.NewTicker(p.linger)
+	defer t.Stop()
+
+	var batch []record
+	for {
+		select {
+		case <-p.done:
+			return
+		case m := <-p.queue:
+			if m.flush != nil {
+				p.process(batch)
+				batch = nil
+				if err := p.spool.flush(); err != nil {
+					p.report("flush spool", err)
+				}
+				close(m.flush)
+				continue
+			}
+			batch = append(batch, record{
+				key:   jsonField(m.value, p.keyField),
+				value: m.value,
+				time:  m.time,
+			})
+			if len(batch) >= p.batchSize {
+				p.process(batch)
+				batch = nil
+			}
+		case <-t.C:
+			p.process(batch)
+			batch = nil
+		}
+	}
+}
+
+func (p *Producer) shutdown() {
+	for _, b := range p.seeds {
+		b.close()
+	}
+	for _, b := range p.brokers {
+		b.close()
+	}
+	if err := p.spool.close(); err != nil {
+		p.report("close spool", err)
+	}
+}
+
+func (p *Producer) process(batch []record) {
+	// 1. 重放本地缓存
+	if !p.spool.empty() {
+		p.replay()
+	}
+	if len(batch) <= 0 {
+		return
+	}
+
+	// 2. 缓存为空时直接发送, 否则追加到缓存, 以保证消息顺序
+	if p.spool.empty() {
+		failed, err := p.produce(batch)
+		if err == nil {
+			return
+		}
+		p.fail("produce", err)
+		batch = failed
+	}
+	if err := p.spool.write(batch); err != nil {
+		p.report("write spool", err)
+		atomic.AddInt64(&p.dropped, int64(len(batch)))
+	}
+}
+
+func (p *Producer) replay() {
+	if p.failed && time.Since(p.failedAt) < p.retryInterval {
+		return
+	}
+	err := p.spool.replay(p.batchSize, func(records []record) error {
+		_, err := p.produce(records)
+		return err
+	})
+	if err != nil {
+		p.fail("replay", err)
+		return
+	}
+	p.failed = false
+}
+
+func (p *Producer) fail(op string, err error) {
+	if !p.failed {
+		p.report(op, err)
+	}
+	p.failed = true
+	p.failedAt = time.Now()
+	p.partitions = nil
+}
+
+// report 输出错误
+func (p *Producer) report(op string, err error) {
+	fmt.Fprintf(os.Stderr, "kafka.Producer: %s %s: %v\n", op, p.topic, err)
+}
+
+// produce 发送消息, 返回发送失败的消息
+func (p *Producer) produce(records []record) ([]record, error) {
+	if len(p.partitions) <= 0 {
+		if err := p.refreshMetadata(); err != nil {
+			return records, err
+		}
+	}
+
+	// 1. 按 leader 及分区分组
+	groups := make(map[int32]map[int32][]record)
+	for _, r := range records {
+		pm := p.partitions[p.part.partition(r.key, len(p.partitions))]
+		parts, ok := groups[pm.leader]
+		if !ok {
+			parts = make(map[int32][]record)
+			groups[pm.leader] = parts
+		}
+		parts[pm.id] = append(parts[pm.id], r)
+	}
+
+	// 2. 向各个 leader 发送
+	var err error
+	var failed []record
+	leaders := make([]int32, 0, len(groups))
+	for leader := range groups {
+		leaders = append(leaders, leader)
+	}
+	sortInt32s(leaders)
+	for _, leader := range leaders {
+		ids := make([]int32, 0, len(groups[leader]))
+		for id := range groups[leader] {
+			ids = append(ids, id)
+		}
+		sortInt32s(ids)
+		parts := make([]partitionRecords, 0, len(ids))
+		for _, id := range ids {
+			parts = append(parts, partitionRecords{partition: id, records: groups[leader][id]})
+		}
+		if e := p.send(leader, parts); e != nil {
+			err = multierr.Append(err, e)
+			for _, pr := range parts {
+				failed = append(failed, pr.records...)
+			}
+		}
+	}
+	return failed, err
+}
+
+func (p *Producer) send(leader int32, parts []partitionRecords) error {
+	b, ok := p.brokers[leader]
+	if !ok {
+		return fmt.Errorf("leader %d is not available", leader)
+	}
+	results, err := b.produce(p.acks, p.timeout, p.topic, parts)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.errorCode != 0 {
+			return fmt.Errorf("partition %d: %w", r.partition, kafkaError(r.errorCode))
+		}
+	}
+	return nil
+}
+
+func (p *Producer) refreshMetadata() (err error) {
+	for _, b := range p.seeds {
+		resp, e := b.metadata(p.topic)
+		if e != nil {
+			err = multierr.Append(err, e)
+			continue
+		}
+		return p.updateMetadata(resp)
+	}
+	return err
+}
+
+func (p *Producer) updateMetadata(resp *metadataResponse) error {
+	var topic *topicMeta
+	for i := range resp.topics {
+		if resp.topics[i].name == p.topic {
+			topic = &resp.topics[i]
+		}
+	}
+	if topic == nil {
+		return fmt.Errorf("topic %q not found", p.topic)
+	}
+	if topic.errorCode != 0 {
+		return fmt.Errorf("topic %q: %w", p.topic, kafkaError(topic.errorCode))
+	}
+	if len(topic.partitions) <= 0 {
+		return fmt.Errorf("topic %q has no partitions", p.topic)
+	}
+
+	brokers := make(map[int32]*broker, len(resp.brokers))
+	for _, bm := range resp.brokers {
+		addr := net.JoinHostPort(bm.host, strconv.Itoa(int(bm.port)))
+		if b, ok := p.brokers[bm.nodeID]; ok && b.addr == addr {
+			brokers[bm.nodeID] = b
+			delete(p.brokers, bm.nodeID)
+		} else {
+			brokers[bm.nodeID] = p.newBroker(addr)
+		}
+	}
+	for _, b := range p.brokers {
+		b.close()
+	}
+	p.brokers = brokers
+
+	partitions := append([]partitionMeta(nil), topic.partitions...)
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].id < partitions[j].id })
+	p.partitions = partitions
+	return nil
+}
+
+func sortInt32s(a []int32) {
+	sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+}
+
+// flush 等待队列中已有的消息发送完毕或写入本地缓存
+func (p *Producer) flush(op string) error {
+	m := message{flush: make(chan struct{})}
+	t := time.NewTimer(p.syncTimeout)
+	defer t.Stop()
+
+	select {
+	case p.queue <- m:
+	case <-t.C:
+		return p.wrapErr(op, errTimeout)
+	}
+	select {
+	case <-m.flush:
+		return nil
+	case <-t.C:
+		return p.wrapErr(op, errTimeout)
+	}
+}
+
+func (p *Producer) wrapErr(op string, err error) error {
+	return &os.PathError{Op: op, Path: p.topic, Err: err}
+}
+
+// Dropped 返回因队列已满或写入缓存失败而丢弃的消息条数
+func (p *Producer) Dropped() int64 {
+	return atomic.LoadInt64(&p.dropped)
+}
+
+// QueueDepth 返回队列中等待发送的消息条数
+func (p *Producer) QueueDepth() int {
+	return len(p.queue)
+}
+
+func (p *Producer) Write(b []byte) (n int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 1. 是否已关闭
+	if p.closed {
+		return 0, p.wrapErr("write", os.ErrClosed)
+	}
+
+	// 2. 放入队列, 调用方会复用 b, 因此需要拷贝
+	value := make([]byte, len(b))
+	copy(value, b)
+	select {
+	case p.queue <- message{value: bytes.TrimSuffix(value, []byte("\n")), time: time.Now()}:
+	default:
+		atomic.AddInt64(&p.dropped, 1)
+	}
+	return len(b), nil
+}
+
+func (p *Producer) Sync() error {
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return p.wrapErr("sync", os.ErrClosed)
+	}
+	return p.flush("sync")
+}
+
+func (p *Producer) Close() (err error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return p.wrapErr("close", os.ErrClosed)
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	err = p.flush("close")
+	close(p.done)
+	<-p.exited
+	return err
+}
@@ -0,0 +1,258 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func WaitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("wait for condition: timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// ReserveTestAddr 返回一个当前无人监听的地址
+func ReserveTestAddr(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		addrs []string
+		topic string
+		opts  []Option
+		err   bool
+	}{
+		{addrs: []string{"127.0.0.1:9092"}, topic: "test"},
+		{addrs: []string{"127.0.0.1:9092"}, topic: "test", opts: []Option{SetAcks(-1)}},
+		{addrs: nil, topic: "test", err: true},
+		{addrs: []string{"127.0.0.1:9092"}, topic: "", err: true},
+		{addrs: []string{"127.0.0.1:9092"}, topic: "test", opts: []Option{SetAcks(2)}, err: true},
+	}
+	for i, tt := range tests {
+		opts := append([]Option{SetSpoolDir("testdata/test_open")}, tt.opts...)
+		p, err := Open(tt.addrs, tt.topic, opts...)
+		if got, want := err != nil, tt.err; got != want {
+			t.Errorf("%d: error: got %v, want %v", i, err, want)
+			continue
+		}
+		if err != nil {
+			t.Logf("%d: open: %v", i, err)
+			continue
+		}
+		p.Close()
+	}
+}
+
+func TestProducerProduce(t *testing.T) {
+	tb := StartTestBroker(t, "127.0.0.1:0", 4)
+	defer tb.Close()
+
+	p, err := Open([]string{tb.Addr()}, "test", SetSpoolDir("testdata/test_producer_produce"), SetBatchSize(3))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer p.Close()
+
+	lines := []string{
+		`{"logger":"access","msg":"1"}`,
+		`{"logger":"error","msg":"2"}`,
+		`{"logger":"access","msg":"3"}`,
+		`{"msg":"4"}`,
+	}
+	for _, line := range lines {
+		fmt.Fprintln(p, line)
+	}
+	if err = p.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	var n int
+	for i := 0; i < 4; i++ {
+		for _, r := range tb.Records(int32(i)) {
+			n++
+			if strings.HasSuffix(string(r.value), "\n") {
+				t.Errorf("value %q: unexpected trailing newline", r.value)
+			}
+			key := jsonField(r.value, "logger")
+			if got, want := string(r.key), string(key); got != want {
+				t.Errorf("value %q: key: got %q, want %q", r.value, got, want)
+			}
+			if key != nil {
+				if got, want := i, int(murmur2(key)&0x7fffffff)%4; got != want {
+					t.Errorf("value %q: partition: got %d, want %d", r.value, got, want)
+				}
+			}
+		}
+	}
+	if got, want := n, len(lines); got != want {
+		t.Errorf("records: got %d, want %d", got, want)
+	}
+	for _, acks := range tb.Acks() {
+		if got, want := acks, int16(1); got != want {
+			t.Errorf("acks: got %d, want %d", got, want)
+		}
+	}
+}
+
+func TestProducerAcks(t *testing.T) {
+	tb := StartTestBroker(t, "127.0.0.1:0", 1)
+	defer tb.Close()
+
+	p, err := Open([]string{tb.Addr()}, "test", SetSpoolDir("testdata/test_producer_acks"), SetAcks(0))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer p.Close()
+
+	p.Write([]byte("1\n"))
+	p.Write([]byte("2\n"))
+	if err = p.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	WaitFor(t, func() bool { return reflect.DeepEqual(tb.Values(), []string{"1", "2"}) })
+	if got, want := tb.Acks(), []int16{0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("acks: got %v, want %v", got, want)
+	}
+}
+
+func TestProducerSpool(t *testing.T) {
+	addr := ReserveTestAddr(t)
+	dir := "testdata/test_producer_spool"
+	p, err := Open([]string{addr}, "test", SetSpoolDir(dir), SetBatchSize(2),
+		SetDialTimeout(100*time.Millisecond), SetRetryInterval(10*time.Millisecond), SetSpoolMaxSize(64))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer p.Close()
+
+	// 1. broker 不可用, 消息写入本地缓存
+	var want []string
+	for i := 0; i < 10; i++ {
+		want = append(want, fmt.Sprint(i))
+		fmt.Fprintln(p, i)
+	}
+	if err = p.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if files, _ := p.spool.files(); len(files) <= 0 {
+		t.Fatalf("no spool files")
+	}
+
+	// 2. broker 恢复后按顺序重放
+	tb := StartTestBroker(t, addr, 1)
+	defer tb.Close()
+	for i := 10; i < 15; i++ {
+		want = append(want, fmt.Sprint(i))
+		fmt.Fprintln(p, i)
+	}
+	WaitFor(t, func() bool {
+		p.Sync()
+		return len(tb.Values()) >= len(want)
+	})
+	if got := tb.Values(); !reflect.DeepEqual(got, want) {
+		t.Errorf("values: got %v, want %v", got, want)
+	}
+	if files, _ := p.spool.files(); len(files) != 0 {
+		t.Errorf("spool files: got %v, want none", files)
+	}
+}
+
+func TestProducerReplayOnOpen(t *testing.T) {
+	dir := "testdata/test_producer_replay_on_open"
+	s := openSpool(dir, 1024, 4)
+	if err := s.write(spoolRecords("a", 3)); err != nil {
+		t.Fatalf("write spool: %v", err)
+	}
+	if err := s.close(); err != nil {
+		t.Fatalf("close spool: %v", err)
+	}
+
+	tb := StartTestBroker(t, "127.0.0.1:0", 1)
+	defer tb.Close()
+
+	p, err := Open([]string{tb.Addr()}, "test", SetSpoolDir(dir))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer p.Close()
+
+	fmt.Fprintln(p, "b-0")
+	if err = p.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got, want := tb.Values(), []string{"a-0", "a-1", "a-2", "b-0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("values: got %v, want %v", got, want)
+	}
+}
+
+func TestProducerBacklog(t *testing.T) {
+	addr := ReserveTestAddr(t)
+	p, err := Open([]string{addr}, "test", SetSpoolDir("testdata/test_producer_backlog"),
+		SetBacklog(1), SetLinger(time.Hour), SetDialTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer p.Close()
+
+	for i := 0; i < 100; i++ {
+		if _, err = p.Write([]byte("x\n")); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if p.Dropped() <= 0 {
+		t.Errorf("dropped: got %d, want > 0", p.Dropped())
+	}
+	if p.QueueDepth() > 1 {
+		t.Errorf("queue depth: got %d, want <= 1", p.QueueDepth())
+	}
+}
+
+func TestProducerClose(t *testing.T) {
+	tb := StartTestBroker(t, "127.0.0.1:0", 1)
+	defer tb.Close()
+
+	p, err := Open([]string{tb.Addr()}, "test", SetSpoolDir("testdata/test_producer_close"), SetLinger(time.Hour))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	p.Write([]byte("1\n"))
+	if err = p.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got, want := tb.Values(), []string{"1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("values: got %v, want %v", got, want)
+	}
+
+	if _, err = p.Write([]byte("2\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("write: got %v, want %v", err, os.ErrClosed)
+	}
+	if err = p.Sync(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("sync: got %v, want %v", err, os.ErrClosed)
+	}
+	if err = p.Close(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("close: got %v, want %v", err, os.ErrClosed)
+	}
+}
+
+func TestMain(m *testing.M) {
+	os.RemoveAll("./testdata")
+	m.Run()
+	os.RemoveAll("./testdata")
+}
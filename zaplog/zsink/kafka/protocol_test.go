@@ -0,0 +1,92 @@
+package kafka
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncoderDecoder(t *testing.T) {
+	var e encoder
+	e.int8(-8)
+	e.int16(-16)
+	e.int32(-32)
+	e.int64(-64)
+	e.bool(true)
+	e.string("hello")
+	e.nullString()
+	e.bytes([]byte("world"))
+	e.bytes(nil)
+	e.varint(-300)
+	e.varbytes([]byte("var"))
+	e.varbytes(nil)
+	e.arrayLen(2)
+
+	d := decoder{b: e.b}
+	if got, want := d.int8(), int8(-8); got != want {
+		t.Errorf("int8: got %v, want %v", got, want)
+	}
+	if got, want := d.int16(), int16(-16); got != want {
+		t.Errorf("int16: got %v, want %v", got, want)
+	}
+	if got, want := d.int32(), int32(-32); got != want {
+		t.Errorf("int32: got %v, want %v", got, want)
+	}
+	if got, want := d.int64(), int64(-64); got != want {
+		t.Errorf("int64: got %v, want %v", got, want)
+	}
+	if got, want := d.bool(), true; got != want {
+		t.Errorf("bool: got %v, want %v", got, want)
+	}
+	if got, want := d.string(), "hello"; got != want {
+		t.Errorf("string: got %v, want %v", got, want)
+	}
+	if got, want := d.string(), ""; got != want {
+		t.Errorf("null string: got %v, want %v", got, want)
+	}
+	if got, want := d.bytes(), []byte("world"); !bytes.Equal(got, want) {
+		t.Errorf("bytes: got %q, want %q", got, want)
+	}
+	if got := d.bytes(); got != nil {
+		t.Errorf("null bytes: got %q, want nil", got)
+	}
+	if got, want := d.varint(), int64(-300); got != want {
+		t.Errorf("varint: got %v, want %v", got, want)
+	}
+	if got, want := d.varbytes(), []byte("var"); !bytes.Equal(got, want) {
+		t.Errorf("varbytes: got %q, want %q", got, want)
+	}
+	if got := d.varbytes(); got != nil {
+		t.Errorf("null varbytes: got %q, want nil", got)
+	}
+	if got, want := d.int32(), int32(2); got != want {
+		t.Errorf("array len: got %v, want %v", got, want)
+	}
+	if d.err != nil {
+		t.Errorf("decode: %v", d.err)
+	}
+	if got := len(d.b); got != 0 {
+		t.Errorf("remain: got %d bytes, want 0", got)
+	}
+}
+
+func TestDecoderShortBuffer(t *testing.T) {
+	tests := []struct {
+		b      []byte
+		decode func(d *decoder)
+	}{
+		{b: []byte{0}, decode: func(d *decoder) { d.int16() }},
+		{b: []byte{0, 0, 0}, decode: func(d *decoder) { d.int32() }},
+		{b: []byte{0, 0, 0, 0}, decode: func(d *decoder) { d.int64() }},
+		{b: []byte{0, 5, 'a'}, decode: func(d *decoder) { d.string() }},
+		{b: []byte{0, 0, 0, 5, 'a'}, decode: func(d *decoder) { d.bytes() }},
+		{b: []byte{0x80}, decode: func(d *decoder) { d.varint() }},
+		{b: []byte{0, 0, 0, 100}, decode: func(d *decoder) { d.arrayLen() }},
+	}
+	for i, tt := range tests {
+		d := decoder{b: tt.b}
+		tt.decode(&d)
+		if got, want := d.err, errShortBuffer; got != want {
+			t.Errorf("%d: error: got %v, want %v", i, got, want)
+		}
+	}
+}
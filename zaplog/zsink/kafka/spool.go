@@ -0,0 +1,198 @@
+package kafka
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ironzhang/tlog/zaplog/zsink/rollfile"
+)
+
+const spoolName = "spool.log"
+
+// spool 在 broker 不可用时将消息缓存到本地 rollfile 目录, 恢复后按写入顺序重放
+type spool struct {
+	dir       string
+	maxSize   int
+	maxSeq    int
+	file      *rollfile.File
+	rotations int64
+	pending   bool
+	offsets   map[string]int
+}
+
+func openSpool(dir string, maxSize, maxSeq int) *spool {
+	s := &spool{
+		dir:     dir,
+		maxSize: maxSize,
+		maxSeq:  maxSeq,
+		offsets: make(map[string]int),
+	}
+	files, _ := s.files()
+	s.pending = len(files) > 0
+	return s
+}
+
+func (s *spool) empty() bool {
+	return !s.pending
+}
+
+func (s *spool) write(records []record) error {
+	if s.file == nil {
+		name := filepath.Join(s.dir, spoolName)
+		// 缓存文件为二进制格式, 不能输出文件创建日志
+		f, err := rollfile.Open(name, rollfile.SetMaxSize(s.maxSize), rollfile.SetMaxSeq(s.maxSeq), rollfile.SetPrintCreateLog(false))
+		if err != nil {
+			return err
+		}
+		s.file = f
+		s.rotations = 0
+	}
+
+	var e encoder
+	for _, r := range records {
+		e.int64(millis(r.time))
+		e.bytes(r.key)
+		e.bytes(r.value)
+	}
+	if _, err := s.file.Write(e.b); err != nil {
+		return err
+	}
+	s.pending = true
+	s.resetOffset()
+	return nil
+}
+
+// resetOffset 在 rollfile 滚动后清除当前文件的重放位置;
+// rollfile 循环使用文件序号, 滚动到的文件被截断重写, 之前记录的位置已失效
+func (s *spool) resetOffset() {
+	n := s.file.Rotations()
+	if n == s.rotations {
+		return
+	}
+	s.rotations = n
+	if link, err := os.Readlink(filepath.Join(s.dir, spoolName)); err == nil {
+		delete(s.offsets, filepath.Base(link))
+	}
+}
+
+func (s *spool) flush() error {
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Flush()
+}
+
+func (s *spool) close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// files 按写入的先后顺序返回缓存文件;
+// rollfile 循环使用文件序号, 符号链接指向的是最新的文件, 序号大于它的文件更早写入
+func (s *spool) files() ([]string, error) {
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, fi := range infos {
+		if fi.Mode().IsRegular() && strings.HasPrefix(fi.Name(), spoolName+".") {
+			names = append(names, fi.Name())
+		}
+	}
+	current := -1
+	if link, err := os.Readlink(filepath.Join(s.dir, spoolName)); err == nil {
+		current = spoolSeq(link)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		si, sj := spoolSeq(names[i]), spoolSeq(names[j])
+		if oi, oj := si <= current, sj <= current; oi != oj {
+			return oj
+		}
+		return si < sj
+	})
+	return names, nil
+}
+
+func spoolSeq(name string) int {
+	n, _ := strconv.Atoi(name[strings.LastIndex(name, ".")+1:])
+	return n
+}
+
+// replay 按顺序重放缓存的消息, 每次最多发送 n 条, 重放完成的文件会被删除;
+// 发送失败时记录已发送的位置, 下次从该位置继续
+func (s *spool) replay(n int, send func([]record) error) error {
+	if err := s.close(); err != nil {
+		return err
+	}
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		if err = s.replayFile(name, n, send); err != nil {
+			return err
+		}
+		os.Remove(filepath.Join(s.dir, name))
+		delete(s.offsets, name)
+	}
+	s.pending = false
+	return nil
+}
+
+func (s *spool) replayFile(name string, n int, send func([]record) error) error {
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		return err
+	}
+	off := s.offsets[name]
+	if off > len(data) {
+		off = 0
+	}
+	for {
+		records, size := decodeSpoolRecords(data[off:], n)
+		if len(records) <= 0 {
+			return nil
+		}
+		if err = send(records); err != nil {
+			return err
+		}
+		off += size
+		s.offsets[name] = off
+	}
+}
+
+// decodeSpoolRecords 解码最多 n 条消息, 返回消息及其占用的字节数, 忽略末尾不完整的消息
+func decodeSpoolRecords(b []byte, n int) ([]record, int) {
+	var records []record
+	d := decoder{b: b}
+	size := 0
+	for len(records) < n && len(d.b) > 0 {
+		ts := d.int64()
+		key := d.bytes()
+		value := d.bytes()
+		if d.err != nil {
+			break
+		}
+		records = append(records, record{
+			key:   key,
+			value: value,
+			time:  time.Unix(0, ts*int64(time.Millisecond)),
+		})
+		size = len(b) - len(d.b)
+	}
+	return records, size
+}
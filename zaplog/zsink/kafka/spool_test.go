@@ -0,0 +1,236 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ironzhang/tlog/zaplog/zsink/rollfile"
+)
+
+func spoolRecords(prefix string, n int) []record {
+	records := make([]record, n)
+	for i := range records {
+		records[i] = record{
+			key:   []byte(prefix),
+			value: []byte(fmt.Sprintf("%s-%d", prefix, i)),
+			time:  time.Unix(0, int64(i)*int64(time.Millisecond)),
+		}
+	}
+	return records
+}
+
+func recordValues(records []record) []string {
+	values := make([]string, 0, len(records))
+	for _, r := range records {
+		values = append(values, string(r.value))
+	}
+	return values
+}
+
+func TestSpoolReplay(t *testing.T) {
+	s := openSpool("testdata/test_spool_replay", 64, 10)
+	if !s.empty() {
+		t.Fatalf("spool is not empty")
+	}
+
+	var want []string
+	for i := 0; i < 5; i++ {
+		records := spoolRecords(fmt.Sprint(i), 3)
+		if err := s.write(records); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		want = append(want, recordValues(records)...)
+	}
+	if s.empty() {
+		t.Fatalf("spool is empty")
+	}
+
+	var got []string
+	err := s.replay(2, func(records []record) error {
+		if len(records) > 2 {
+			t.Errorf("replay batch: got %d records, want <= 2", len(records))
+		}
+		got = append(got, recordValues(records)...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("values: got %v, want %v", got, want)
+	}
+	if !s.empty() {
+		t.Errorf("spool is not empty")
+	}
+	if files, _ := s.files(); len(files) != 0 {
+		t.Errorf("files: got %v, want none", files)
+	}
+
+	// 重新打开时不应有残留
+	if s = openSpool("testdata/test_spool_replay", 64, 10); !s.empty() {
+		t.Errorf("reopened spool is not empty")
+	}
+}
+
+func TestSpoolReplayWrap(t *testing.T) {
+	s := openSpool("testdata/test_spool_replay_wrap", 32, 3)
+
+	// 每次写入 60 字节, 都会滚动到新文件, 超过 3 个文件后最早的数据被覆盖
+	var want []string
+	for i := 0; i < 5; i++ {
+		records := spoolRecords(fmt.Sprint(i), 3)
+		if err := s.write(records); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if i >= 2 {
+			want = append(want, recordValues(records)...)
+		}
+	}
+
+	var got []string
+	err := s.replay(10, func(records []record) error {
+		got = append(got, recordValues(records)...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("values: got %v, want %v", got, want)
+	}
+}
+
+func TestSpoolReplayResume(t *testing.T) {
+	s := openSpool("testdata/test_spool_replay_resume", 1024, 10)
+	records := spoolRecords("a", 6)
+	if err := s.write(records); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var got []string
+	calls := 0
+	err := s.replay(2, func(records []record) error {
+		calls++
+		if calls == 2 {
+			return errors.New("broker is not available")
+		}
+		got = append(got, recordValues(records)...)
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("replay: expected error")
+	}
+	if s.empty() {
+		t.Fatalf("spool is empty after failed replay")
+	}
+
+	err = s.replay(2, func(records []record) error {
+		got = append(got, recordValues(records)...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if want := recordValues(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("values: got %v, want %v", got, want)
+	}
+}
+
+func TestSpoolReplayResumeWrap(t *testing.T) {
+	dir := "testdata/test_spool_replay_resume_wrap"
+	os.RemoveAll(dir)
+	s := openSpool(dir, 32, 2)
+	if err := s.write(spoolRecords("a", 3)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// 只重放了 a-0, 记录了 spool.log.0 的重放位置
+	calls := 0
+	err := s.replay(1, func(records []record) error {
+		if calls++; calls == 2 {
+			return errors.New("broker is not available")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("replay: expected error")
+	}
+
+	// 每次写入都滚动到新文件, c 循环写入 spool.log.0, 之前的重放位置不再有效
+	var want []string
+	for _, prefix := range []string{"b", "c"} {
+		records := spoolRecords(prefix, 3)
+		if err = s.write(records); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		want = append(want, recordValues(records)...)
+	}
+
+	var got []string
+	err = s.replay(10, func(records []record) error {
+		got = append(got, recordValues(records)...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("values: got %v, want %v", got, want)
+	}
+}
+
+func TestSpoolPrintCreateLog(t *testing.T) {
+	rollfile.PrintCreateLog = true
+	defer func() { rollfile.PrintCreateLog = false }()
+
+	s := openSpool("testdata/test_spool_print_create_log", 1024, 10)
+	records := spoolRecords("a", 3)
+	if err := s.write(records); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var got []string
+	err := s.replay(10, func(records []record) error {
+		got = append(got, recordValues(records)...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if want := recordValues(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("values: got %v, want %v", got, want)
+	}
+}
+
+func TestDecodeSpoolRecords(t *testing.T) {
+	var e encoder
+	for _, r := range spoolRecords("a", 2) {
+		e.int64(millis(r.time))
+		e.bytes(r.key)
+		e.bytes(r.value)
+	}
+	full := len(e.b)
+	e.int64(0)
+	e.int32(8)
+	e.b = append(e.b, "trunc"...)
+
+	records, size := decodeSpoolRecords(e.b, 10)
+	if got, want := recordValues(records), []string{"a-0", "a-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("values: got %v, want %v", got, want)
+	}
+	if got, want := size, full; got != want {
+		t.Errorf("size: got %d, want %d", got, want)
+	}
+	if got, want := millis(records[1].time), int64(1); got != want {
+		t.Errorf("time: got %d, want %d", got, want)
+	}
+
+	records, _ = decodeSpoolRecords(e.b, 1)
+	if got, want := len(records), 1; got != want {
+		t.Errorf("records: got %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package zsink
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/ironzhang/tlog/zaplog/zsink/kafka"
+)
+
+const defaultKafkaPort = "9092"
+
+func newKafkaSink(u *url.URL) (zap.Sink, error) {
+	addrs, topic, err := parseKafkaAddrs(u)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseKafkaOptions(u)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := kafka.Open(addrs, topic, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return producer, nil
+}
+
+func parseKafkaAddrs(u *url.URL) (addrs []string, topic string, err error) {
+	for _, host := range strings.Split(u.Host, ",") {
+		if host == "" {
+			continue
+		}
+		if _, _, err = net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, defaultKafkaPort)
+		}
+		addrs = append(addrs, host)
+	}
+	if len(addrs) <= 0 {
+		return nil, "", fmt.Errorf("no brokers in %q", u.String())
+	}
+	topic = strings.Trim(u.Path, "/")
+	if topic == "" || strings.Contains(topic, "/") {
+		return nil, "", fmt.Errorf("invalid topic %q", topic)
+	}
+	return addrs, topic, nil
+}
+
+func parseKafkaOptions(u *url.URL) (opts []kafka.Option, err error) {
+	params := values(u.Query())
+
+	acks, ok := params.Get("acks")
+	if ok {
+		n, err := stringToAcks(acks)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kafka.SetAcks(n))
+	}
+
+	batchSize, ok, err := params.GetInt("batchSize")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, kafka.SetBatchSize(batchSize))
+	}
+
+	linger, ok, err := params.GetDuration("linger")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, kafka.SetLinger(linger))
+	}
+
+	backlog, ok, err := params.GetInt("backlog")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, kafka.SetBacklog(backlog))
+	}
+
+	if clientID, ok := params.Get("clientID"); ok {
+		opts = append(opts, kafka.SetClientID(clientID))
+	}
+	if key, ok := params.Get("key"); ok {
+		opts = append(opts, kafka.SetKeyField(key))
+	}
+
+	timeout, ok, err := params.GetDuration("timeout")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, kafka.SetTimeout(timeout))
+	}
+
+	dialTimeout, ok, err := params.GetDuration("dialTimeout")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, kafka.SetDialTimeout(dialTimeout))
+	}
+
+	retryInterval, ok, err := params.GetDuration("retryInterval")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, kafka.SetRetryInterval(retryInterval))
+	}
+
+	syncTimeout, ok, err := params.GetDuration("syncTimeout")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, kafka.SetSyncTimeout(syncTimeout))
+	}
+
+	if spool, ok := params.Get("spool"); ok {
+		opts = append(opts, kafka.SetSpoolDir(spool))
+	}
+
+	spoolMaxSize, ok, err := params.GetSize("spoolMaxSize")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, kafka.SetSpoolMaxSize(spoolMaxSize))
+	}
+
+	spoolMaxSeq, ok, err := params.GetInt("spoolMaxSeq")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, kafka.SetSpoolMaxSeq(spoolMaxSeq))
+	}
+
+	return opts, nil
+}
+
+func stringToAcks(s string) (int16, error) {
+	switch strings.ToLower(s) {
+	case "0", "none":
+		return 0, nil
+	case "1", "leader":
+		return 1, nil
+	case "-1", "all":
+		return -1, nil
+	default:
+		return 0, fmt.Errorf("unknown acks %q", s)
+	}
+}
+
+func init() {
+	if err := RegisterSink("kafka", newKafkaSink); err != nil {
+		panic(err)
+	}
+}
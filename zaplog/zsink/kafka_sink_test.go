@@ -0,0 +1,116 @@
+package zsink
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseKafkaAddrs(t *testing.T) {
+	tests := []struct {
+		url   *url.URL
+		err   string
+		addrs []string
+		topic string
+	}{
+		{
+			url:   ParseTestURL(t, "kafka://127.0.0.1:9093/access"),
+			addrs: []string{"127.0.0.1:9093"},
+			topic: "access",
+		},
+		{
+			url:   ParseTestURL(t, "kafka://broker1,broker2:9093/access"),
+			addrs: []string{"broker1:9092", "broker2:9093"},
+			topic: "access",
+		},
+		{
+			url: ParseTestURL(t, "kafka:///access"),
+			err: "no brokers",
+		},
+		{
+			url: ParseTestURL(t, "kafka://broker1"),
+			err: "invalid topic",
+		},
+		{
+			url: ParseTestURL(t, "kafka://broker1/a/b"),
+			err: "invalid topic",
+		},
+	}
+	for i, tt := range tests {
+		addrs, topic, err := parseKafkaAddrs(tt.url)
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: got %v, want %v", i, err, tt.err)
+			continue
+		}
+		if err != nil {
+			t.Logf("%d: parse kafka addrs: %v", i, err)
+			continue
+		}
+		if got, want := addrs, tt.addrs; !reflect.DeepEqual(got, want) {
+			t.Errorf("%d: addrs: got %v, want %v", i, got, want)
+		}
+		if got, want := topic, tt.topic; got != want {
+			t.Errorf("%d: topic: got %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestStringToAcks(t *testing.T) {
+	tests := []struct {
+		s    string
+		acks int16
+		err  string
+	}{
+		{s: "0", acks: 0},
+		{s: "none", acks: 0},
+		{s: "1", acks: 1},
+		{s: "Leader", acks: 1},
+		{s: "-1", acks: -1},
+		{s: "all", acks: -1},
+		{s: "2", err: "unknown acks"},
+	}
+	for i, tt := range tests {
+		acks, err := stringToAcks(tt.s)
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: got %v, want %v", i, err, tt.err)
+			continue
+		}
+		if got, want := acks, tt.acks; got != want {
+			t.Errorf("%d: acks: got %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestNewKafkaSink(t *testing.T) {
+	tests := []struct {
+		url *url.URL
+		err string
+	}{
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?spool=testdata/kafka&syncTimeout=10ms")},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?spool=testdata/kafka&acks=all&batchSize=10&linger=10ms&backlog=16")},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?spool=testdata/kafka&clientID=app&key=name&timeout=1s&dialTimeout=1s")},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?spool=testdata/kafka&retryInterval=1s&spoolMaxSize=1MB&spoolMaxSeq=4")},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?acks=2"), err: "unknown acks"},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?batchSize=a"), err: "strconv.Atoi"},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?linger=1"), err: "missing unit"},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?backlog=a"), err: "strconv.Atoi"},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?timeout=1"), err: "missing unit"},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?dialTimeout=1"), err: "missing unit"},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?retryInterval=1"), err: "missing unit"},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?syncTimeout=1"), err: "missing unit"},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1/access?spoolMaxSeq=a"), err: "strconv.Atoi"},
+		{url: ParseTestURL(t, "kafka://127.0.0.1:1"), err: "invalid topic"},
+	}
+	for i, tt := range tests {
+		sink, err := newKafkaSink(tt.url)
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: %v", i, err)
+			continue
+		}
+		if err != nil {
+			t.Logf("%d: new kafka sink: %v", i, err)
+			continue
+		}
+		sink.Close()
+	}
+}
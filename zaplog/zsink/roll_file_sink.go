@@ -82,7 +82,7 @@ func stringToCutFormat(s string) (rollfile.CutFormat, error) {
 }
 
 func init() {
-	if err := zap.RegisterSink("rfile", newRollFileSink); err != nil {
+	if err := RegisterSink("rfile", newRollFileSink); err != nil {
 		panic(err)
 	}
 }
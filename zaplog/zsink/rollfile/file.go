@@ -55,21 +55,24 @@ type File struct {
 	flushedAt time.Time
 	closed    bool
 	done      chan struct{}
-
-	dir     string
-	name    string
-	cutFmt  CutFormat
-	maxSeq  int
-	maxSize int
+	rotations int64
+
+	dir       string
+	name      string
+	cutFmt    CutFormat
+	maxSeq    int
+	maxSize   int
+	createLog bool
 }
 
 func Open(name string, opts ...Option) (*File, error) {
 	f := &File{
-		dir:     filepath.Dir(name),
-		name:    filepath.Base(name),
-		cutFmt:  SizeCut,
-		maxSeq:  0,
-		maxSize: 0,
+		dir:       filepath.Dir(name),
+		name:      filepath.Base(name),
+		cutFmt:    SizeCut,
+		maxSeq:    0,
+		maxSize:   0,
+		createLog: PrintCreateLog,
 	}
 	for _, opt := range opts {
 		opt(f)
@@ -143,7 +146,7 @@ func (f *File) open(t time.Time) error {
 	f.flushedAt = t
 
 	// 3. 输出文件打开日志
-	if PrintCreateLog && f.size <= 0 {
+	if f.createLog && f.size <= 0 {
 		f.size, err = fmt.Fprintf(f.file, "Log file created at: %s\n", t.Format(time.RFC3339Nano))
 		if err != nil {
 			return err
@@ -181,7 +184,11 @@ func (f *File) rotate(t time.Time) error {
 		f.file.Close()
 	}
 
-	// 2. 创建目标文件
+	// 2. 创建目标文件, 先递增 seq, 以免覆盖当前文件
+	f.seq++
+	if f.seq < 0 || f.seq >= f.maxSeq {
+		f.seq = 0
+	}
 	filename := f.baseName(t)
 	file, err := createFile(f.dir, filename, f.name)
 	if err != nil {
@@ -193,13 +200,10 @@ func (f *File) rotate(t time.Time) error {
 	f.size = 0
 	f.createdAt = t
 	f.flushedAt = t
-	f.seq++
-	if f.seq < 0 || f.seq >= f.maxSeq {
-		f.seq = 0
-	}
+	f.rotations++
 
 	// 3. 输出文件打开日志
-	if PrintCreateLog {
+	if f.createLog {
 		f.size, err = fmt.Fprintf(f.file, "Log file created at: %s\n", t.Format(time.RFC3339Nano))
 		if err != nil {
 			return err
@@ -287,6 +291,13 @@ func (f *File) Write(p []byte) (n int, err error) {
 	return n, nil
 }
 
+// Rotations 返回文件滚动的次数
+func (f *File) Rotations() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotations
+}
+
 func (f *File) Flush() (err error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -3,6 +3,8 @@ package rollfile
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"testing"
 )
 
@@ -134,6 +136,61 @@ func TestFileSetMaxSeq(t *testing.T) {
 	PrintTestData(t, f, 1024, "Hello, world\n")
 }
 
+func TestFileRotateKeepData(t *testing.T) {
+	f, err := Open("./testdata/test_file_rotate_keep_data/file.log", SetMaxSize(10), SetMaxSeq(3))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		fmt.Fprintf(f, "%d-Hello, world\n", i)
+	}
+	if got, want := f.Rotations(), int64(2); got != want {
+		t.Errorf("rotations: got %d, want %d", got, want)
+	}
+	f.Close()
+
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("./testdata/test_file_rotate_keep_data/file.log.%d", i)
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatalf("read file: %v", err)
+		}
+		if got, want := string(data), fmt.Sprintf("%d-Hello, world\n", i); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestFileReopenRotate 重新打开文件后的第一次滚动不应覆盖重新打开的文件
+func TestFileReopenRotate(t *testing.T) {
+	dir := "./testdata/test_file_reopen_rotate"
+	os.RemoveAll(dir)
+
+	f, err := Open(dir+"/file.log", SetMaxSize(10), SetMaxSeq(3))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	fmt.Fprintf(f, "0-Hello, world\n")
+	f.Close()
+
+	if f, err = Open(dir+"/file.log", SetMaxSize(10), SetMaxSeq(3)); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	fmt.Fprintf(f, "1-Hello, world\n")
+	f.Close()
+
+	for i := 0; i < 2; i++ {
+		name := fmt.Sprintf("%s/file.log.%d", dir, i)
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatalf("read file: %v", err)
+		}
+		if got, want := string(data), fmt.Sprintf("%d-Hello, world\n", i); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
+
 func TestFileSetMaxSize(t *testing.T) {
 	f, err := Open("./testdata/test_file_size/file.log", SetMaxSize(1024))
 	if err != nil {
@@ -19,3 +19,10 @@ func SetMaxSize(maxSize int) Option {
 		f.maxSize = maxSize
 	}
 }
+
+// SetPrintCreateLog 设置是否在新文件开头输出文件创建日志, 默认为 PrintCreateLog
+func SetPrintCreateLog(print bool) Option {
+	return func(f *File) {
+		f.createLog = print
+	}
+}
@@ -21,6 +21,10 @@ func TestOption(t *testing.T) {
 			opt: SetMaxSize(2),
 			chk: func(f *File) bool { return f.maxSize == 2 },
 		},
+		{
+			opt: SetPrintCreateLog(true),
+			chk: func(f *File) bool { return f.createLog },
+		},
 	}
 	for i, tt := range tests {
 		f := &File{}
@@ -0,0 +1,103 @@
+package zsink
+
+import (
+	"net/url"
+
+	"go.uber.org/zap"
+
+	"github.com/ironzhang/tlog/zaplog/zsink/unixsock"
+)
+
+func newUnixSink(u *url.URL) (zap.Sink, error) {
+	return openUnixSock("unix", u)
+}
+
+func newUnixgramSink(u *url.URL) (zap.Sink, error) {
+	return openUnixSock("unixgram", u)
+}
+
+func openUnixSock(network string, u *url.URL) (zap.Sink, error) {
+	addr, err := parseSockPath(u)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseSockOptions(u)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := unixsock.Open(network, addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func parseSockPath(u *url.URL) (string, error) {
+	if u.Host == "" {
+		return u.Path, nil
+	}
+	return parseFilePath(u)
+}
+
+func parseSockOptions(u *url.URL) (opts []unixsock.Option, err error) {
+	params := values(u.Query())
+
+	backlog, ok, err := params.GetInt("backlog")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, unixsock.SetBacklog(backlog))
+	}
+
+	dialTimeout, ok, err := params.GetDuration("dialTimeout")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, unixsock.SetDialTimeout(dialTimeout))
+	}
+
+	writeTimeout, ok, err := params.GetDuration("writeTimeout")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, unixsock.SetWriteTimeout(writeTimeout))
+	}
+
+	retryInterval, ok, err := params.GetDuration("retryInterval")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, unixsock.SetRetryInterval(retryInterval))
+	}
+
+	syncTimeout, ok, err := params.GetDuration("syncTimeout")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, unixsock.SetSyncTimeout(syncTimeout))
+	}
+
+	maxRetries, ok, err := params.GetInt("maxRetries")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, unixsock.SetMaxRetries(maxRetries))
+	}
+
+	return opts, nil
+}
+
+func init() {
+	if err := RegisterSink("unix", newUnixSink); err != nil {
+		panic(err)
+	}
+	if err := RegisterSink("unixgram", newUnixgramSink); err != nil {
+		panic(err)
+	}
+}
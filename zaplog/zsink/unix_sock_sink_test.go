@@ -0,0 +1,83 @@
+package zsink
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseSockPath(t *testing.T) {
+	tests := []struct {
+		url  *url.URL
+		err  string
+		path string
+	}{
+		{
+			url:  ParseTestURL(t, "unix:///var/run/agent.sock"),
+			path: "/var/run/agent.sock",
+		},
+		{
+			url:  ParseTestURL(t, "unixgram://rootdir/var/run/agent.sock"),
+			path: "/var/run/agent.sock",
+		},
+		{
+			url:  ParseTestURL(t, "unix://workdir/run/agent.sock"),
+			path: "run/agent.sock",
+		},
+		{
+			url: ParseTestURL(t, "unix://agent/run/agent.sock"),
+			err: "invalid hostname",
+		},
+	}
+	for i, tt := range tests {
+		path, err := parseSockPath(tt.url)
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: got %v, want %v", i, err, tt.err)
+			continue
+		}
+		if err != nil {
+			t.Logf("%d: parse sock path: %v", i, err)
+			continue
+		}
+		if got, want := path, tt.path; got != want {
+			t.Errorf("%d: path: got %v, want %v", i, got, want)
+			continue
+		}
+	}
+}
+
+func TestNewUnixSink(t *testing.T) {
+	tests := []struct {
+		url *url.URL
+		err string
+	}{
+		{url: ParseTestURL(t, "unix://workdir/testdata/agent.sock")},
+		{url: ParseTestURL(t, "unix://workdir/testdata/agent.sock?backlog=16&syncTimeout=10ms")},
+		{url: ParseTestURL(t, "unix://workdir/testdata/agent.sock?dialTimeout=1s&writeTimeout=1s&retryInterval=1s&maxRetries=1")},
+		{url: ParseTestURL(t, "unix://workdir2/testdata/agent.sock"), err: "invalid hostname"},
+		{url: ParseTestURL(t, "unix://workdir/testdata/agent.sock?backlog=a"), err: "strconv.Atoi"},
+		{url: ParseTestURL(t, "unix://workdir/testdata/agent.sock?dialTimeout=1"), err: "missing unit"},
+		{url: ParseTestURL(t, "unix://workdir/testdata/agent.sock?writeTimeout=1"), err: "missing unit"},
+		{url: ParseTestURL(t, "unix://workdir/testdata/agent.sock?retryInterval=1"), err: "missing unit"},
+		{url: ParseTestURL(t, "unix://workdir/testdata/agent.sock?syncTimeout=1"), err: "missing unit"},
+	}
+	for i, tt := range tests {
+		sink, err := newUnixSink(tt.url)
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: %v", i, err)
+			continue
+		}
+		if err != nil {
+			t.Logf("%d: new unix sink: %v", i, err)
+			continue
+		}
+		sink.Close()
+	}
+}
+
+func TestNewUnixgramSink(t *testing.T) {
+	sink, err := newUnixgramSink(ParseTestURL(t, "unixgram://workdir/testdata/agent.sock?syncTimeout=10ms"))
+	if err != nil {
+		t.Fatalf("new unixgram sink: %v", err)
+	}
+	sink.Close()
+}
@@ -0,0 +1,258 @@
+package unixsock
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+var errTimeout = errors.New("i/o timeout")
+
+const (
+	defaultBacklog       = 4096
+	defaultDialTimeout   = 1 * time.Second
+	defaultWriteTimeout  = 1 * time.Second
+	defaultRetryInterval = 1 * time.Second
+	defaultSyncTimeout   = 3 * time.Second
+	defaultMaxRetries    = 3
+)
+
+type message struct {
+	data  []byte
+	flush chan struct{}
+}
+
+// Conn 是一个写入 unix 域套接字的日志输出,
+// 写入操作只将数据放入有界队列, 由后台协程负责发送, 连接断开时自动重连,
+// 队列已满时丢弃新写入的数据, 写入失败且不可重试或重试次数超过 maxRetries 时丢弃该条数据.
+type Conn struct {
+	mu      sync.Mutex
+	closed  bool
+	queue   chan message
+	done    chan struct{}
+	exited  chan struct{}
+	dropped int64
+
+	conn   net.Conn
+	failed bool
+
+	network       string
+	addr          string
+	backlog       int
+	dialTimeout   time.Duration
+	writeTimeout  time.Duration
+	retryInterval time.Duration
+	syncTimeout   time.Duration
+	maxRetries    int
+}
+
+func Open(network, addr string, opts ...Option) (*Conn, error) {
+	switch network {
+	case "unix", "unixgram":
+	default:
+		return nil, &os.PathError{Op: "open", Path: addr, Err: net.UnknownNetworkError(network)}
+	}
+
+	c := &Conn{
+		network:       network,
+		addr:          addr,
+		backlog:       defaultBacklog,
+		dialTimeout:   defaultDialTimeout,
+		writeTimeout:  defaultWriteTimeout,
+		retryInterval: defaultRetryInterval,
+		syncTimeout:   defaultSyncTimeout,
+		maxRetries:    defaultMaxRetries,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.backlog <= 0 {
+		c.backlog = defaultBacklog
+	}
+	if c.retryInterval <= 0 {
+		c.retryInterval = defaultRetryInterval
+	}
+
+	c.queue = make(chan message, c.backlog)
+	c.done = make(chan struct{})
+	c.exited = make(chan struct{})
+	go c.running()
+
+	return c, nil
+}
+
+func (c *Conn) running() {
+	defer close(c.exited)
+	defer c.disconnect()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case m := <-c.queue:
+			if !c.send(m) {
+				return
+			}
+		}
+	}
+}
+
+// send 发送消息, 失败时按 retryInterval 重试, 直至发送成功或连接关闭;
+// 建立连接失败时一直重试, 写入失败且不可重试或写入失败次数超过 maxRetries 时丢弃该消息
+func (c *Conn) send(m message) bool {
+	if m.flush != nil {
+		close(m.flush)
+		return true
+	}
+
+	for retries := 0; ; {
+		dialed, err := c.write(m.data)
+		if err == nil {
+			return true
+		}
+		if dialed {
+			if !retryable(err) || retries >= c.maxRetries {
+				atomic.AddInt64(&c.dropped, 1)
+				return true
+			}
+			retries++
+		}
+
+		t := time.NewTimer(c.retryInterval)
+		select {
+		case <-c.done:
+			t.Stop()
+			return false
+		case <-t.C:
+		}
+	}
+}
+
+// write 写入数据, dialed 表示连接已建立, 即错误来自写入
+func (c *Conn) write(data []byte) (dialed bool, err error) {
+	// 1. 建立连接
+	if c.conn == nil {
+		conn, err := net.DialTimeout(c.network, c.addr, c.dialTimeout)
+		if err != nil {
+			c.report("dial", err)
+			return false, err
+		}
+		c.conn = conn
+		c.failed = false
+	}
+
+	// 2. 写入数据
+	if c.writeTimeout > 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	}
+	if _, err := c.conn.Write(data); err != nil {
+		c.report("write", err)
+		c.disconnect()
+		return true, err
+	}
+	return true, nil
+}
+
+// retryable 判断写入错误是否可重试, 如 unixgram 数据报超过长度限制时重试无意义
+func retryable(err error) bool {
+	return !errors.Is(err, syscall.EMSGSIZE)
+}
+
+func (c *Conn) disconnect() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// report 输出连接错误, 连接恢复前只输出一次
+func (c *Conn) report(op string, err error) {
+	if c.failed {
+		return
+	}
+	c.failed = true
+	fmt.Fprintf(os.Stderr, "unixsock.Conn: %s %s: %v\n", op, c.addr, err)
+}
+
+// flush 等待队列中已有的数据发送完毕
+func (c *Conn) flush(op string) error {
+	m := message{flush: make(chan struct{})}
+	t := time.NewTimer(c.syncTimeout)
+	defer t.Stop()
+
+	select {
+	case c.queue <- m:
+	case <-t.C:
+		return c.wrapErr(op, errTimeout)
+	}
+	select {
+	case <-m.flush:
+		return nil
+	case <-t.C:
+		return c.wrapErr(op, errTimeout)
+	}
+}
+
+func (c *Conn) wrapErr(op string, err error) error {
+	return &os.PathError{Op: op, Path: c.addr, Err: err}
+}
+
+// Dropped 返回因队列已满或发送失败而丢弃的写入次数
+func (c *Conn) Dropped() int64 {
+	return atomic.LoadInt64(&c.dropped)
+}
+
+// QueueDepth 返回队列中等待发送的数据条数
+func (c *Conn) QueueDepth() int {
+	return len(c.queue)
+}
+
+func (c *Conn) Write(p []byte) (n int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 1. 是否已关闭
+	if c.closed {
+		return 0, c.wrapErr("write", os.ErrClosed)
+	}
+
+	// 2. 放入队列, 调用方会复用 p, 因此需要拷贝
+	data := make([]byte, len(p))
+	copy(data, p)
+	select {
+	case c.queue <- message{data: data}:
+	default:
+		atomic.AddInt64(&c.dropped, 1)
+	}
+	return len(p), nil
+}
+
+func (c *Conn) Sync() error {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return c.wrapErr("sync", os.ErrClosed)
+	}
+	return c.flush("sync")
+}
+
+func (c *Conn) Close() (err error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return c.wrapErr("close", os.ErrClosed)
+	}
+	c.closed = true
+	c.mu.Unlock()
+
+	err = c.flush("close")
+	close(c.done)
+	<-c.exited
+	return err
+}
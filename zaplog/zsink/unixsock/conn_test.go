@@ -0,0 +1,304 @@
+package unixsock
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type tServer struct {
+	ln    net.Listener
+	mu    sync.Mutex
+	wg    sync.WaitGroup
+	bs    bytes.Buffer
+	conns []net.Conn
+}
+
+func ListenTestServer(t *testing.T, addr string) *tServer {
+	os.MkdirAll(filepath.Dir(addr), os.ModePerm)
+	os.Remove(addr)
+	ln, err := net.Listen("unix", addr)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &tServer{ln: ln}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+func (s *tServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			buf := make([]byte, 1024)
+			for {
+				n, err := conn.Read(buf)
+				s.mu.Lock()
+				s.bs.Write(buf[:n])
+				s.mu.Unlock()
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+func (s *tServer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bs.String()
+}
+
+func (s *tServer) Close() {
+	s.ln.Close()
+	s.mu.Lock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+}
+
+func (s *tServer) Wait() {
+	s.wg.Wait()
+}
+
+func WaitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("wait for condition: timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		network string
+		err     bool
+	}{
+		{network: "unix"},
+		{network: "unixgram"},
+		{network: "tcp", err: true},
+	}
+	for i, tt := range tests {
+		c, err := Open(tt.network, "testdata/test_open/agent.sock", SetSyncTimeout(10*time.Millisecond))
+		if got, want := err != nil, tt.err; got != want {
+			t.Errorf("%d: open: got %v, want error %v", i, err, want)
+			continue
+		}
+		if err != nil {
+			t.Logf("%d: open: %v", i, err)
+			continue
+		}
+		c.Close()
+	}
+}
+
+func TestConnWrite(t *testing.T) {
+	addr := "testdata/test_conn_write/agent.sock"
+	s := ListenTestServer(t, addr)
+	defer s.Close()
+
+	c, err := Open("unix", addr)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	data := []byte("hello, world\n")
+	n, err := c.Write(data)
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got, want := n, len(data); got != want {
+		t.Errorf("n: got %d, want %d", got, want)
+	}
+	if err = c.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	WaitFor(t, func() bool { return s.String() == string(data) })
+}
+
+func TestConnReconnect(t *testing.T) {
+	addr := "testdata/test_conn_reconnect/agent.sock"
+	c, err := Open("unix", addr, SetRetryInterval(10*time.Millisecond), SetSyncTimeout(500*time.Millisecond))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer c.Close()
+
+	// 服务未启动, 数据保留在队列中
+	c.Write([]byte("1\n"))
+	if err = c.Sync(); err != nil {
+		t.Logf("sync: %v", err)
+	}
+
+	// 服务启动后, 自动重连并发送
+	s1 := ListenTestServer(t, addr)
+	c.Write([]byte("2\n"))
+	if err = c.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	WaitFor(t, func() bool { return s1.String() == "1\n2\n" })
+
+	// 服务重启后, 自动重连并发送
+	s1.Close()
+	s1.Wait()
+	s2 := ListenTestServer(t, addr)
+	defer s2.Close()
+	WaitFor(t, func() bool {
+		c.Write([]byte("3\n"))
+		c.Sync()
+		return s2.String() != ""
+	})
+}
+
+func TestConnBacklog(t *testing.T) {
+	c, err := Open("unix", "testdata/test_conn_backlog/agent.sock",
+		SetBacklog(2), SetRetryInterval(time.Hour), SetSyncTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer c.Close()
+
+	for i := 0; i < 10; i++ {
+		if _, err = c.Write([]byte("hello\n")); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if got, want := c.QueueDepth(), 2; got > want {
+		t.Errorf("queue depth: got %d, want <= %d", got, want)
+	}
+	if got, want := c.Dropped(), int64(7); got < want {
+		t.Errorf("dropped: got %d, want >= %d", got, want)
+	}
+}
+
+func TestConnUnixgram(t *testing.T) {
+	addr := "testdata/test_conn_unixgram/agent.sock"
+	os.MkdirAll(filepath.Dir(addr), os.ModePerm)
+	os.Remove(addr)
+	pc, err := net.ListenPacket("unixgram", addr)
+	if err != nil {
+		t.Fatalf("listen packet: %v", err)
+	}
+	defer pc.Close()
+
+	c, err := Open("unixgram", addr)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer c.Close()
+
+	c.Write([]byte("hello"))
+	c.Write([]byte("world"))
+
+	buf := make([]byte, 1024)
+	for _, want := range []string{"hello", "world"} {
+		pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("read from: %v", err)
+		}
+		if got := string(buf[:n]); got != want {
+			t.Errorf("datagram: got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestConnDropOversized(t *testing.T) {
+	addr := "testdata/test_conn_drop_oversized/agent.sock"
+	os.MkdirAll(filepath.Dir(addr), os.ModePerm)
+	os.Remove(addr)
+	pc, err := net.ListenPacket("unixgram", addr)
+	if err != nil {
+		t.Fatalf("listen packet: %v", err)
+	}
+	defer pc.Close()
+
+	c, err := Open("unixgram", addr, SetRetryInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer c.Close()
+
+	// 超过数据报长度限制的数据被丢弃, 不阻塞后续数据
+	c.Write(make([]byte, 1<<20))
+	c.Write([]byte("hello"))
+
+	buf := make([]byte, 1024)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read from: %v", err)
+	}
+	if got, want := string(buf[:n]), "hello"; got != want {
+		t.Errorf("datagram: got %q, want %q", got, want)
+	}
+	if got, want := c.Dropped(), int64(1); got != want {
+		t.Errorf("dropped: got %d, want %d", got, want)
+	}
+}
+
+func TestConnMaxRetries(t *testing.T) {
+	addr := "testdata/test_conn_max_retries/agent.sock"
+	os.MkdirAll(filepath.Dir(addr), os.ModePerm)
+	os.Remove(addr)
+	pc, err := net.ListenPacket("unixgram", addr)
+	if err != nil {
+		t.Fatalf("listen packet: %v", err)
+	}
+	defer pc.Close()
+
+	// 写入超时为 1ns, 写入总是失败, 重试 2 次后丢弃
+	c, err := Open("unixgram", addr, SetWriteTimeout(time.Nanosecond), SetRetryInterval(time.Millisecond), SetMaxRetries(2))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer c.Close()
+
+	c.Write([]byte("hello"))
+	c.Write([]byte("world"))
+	WaitFor(t, func() bool { return c.Dropped() == 2 })
+}
+
+func TestConnClose(t *testing.T) {
+	c, err := Open("unix", "testdata/test_conn_close/agent.sock", SetSyncTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err = c.Close(); err != nil {
+		t.Errorf("close: %v", err)
+	}
+	if err = c.Close(); err != nil {
+		t.Logf("close: %v", err)
+	}
+	if _, err = c.Write([]byte("hello")); err == nil {
+		t.Errorf("write after close: expected error")
+	}
+	if err = c.Sync(); err == nil {
+		t.Errorf("sync after close: expected error")
+	}
+}
+
+func TestMain(m *testing.M) {
+	os.RemoveAll("./testdata")
+	m.Run()
+	os.RemoveAll("./testdata")
+}
@@ -0,0 +1,42 @@
+package unixsock
+
+import "time"
+
+type Option func(*Conn)
+
+func SetBacklog(backlog int) Option {
+	return func(c *Conn) {
+		c.backlog = backlog
+	}
+}
+
+func SetDialTimeout(d time.Duration) Option {
+	return func(c *Conn) {
+		c.dialTimeout = d
+	}
+}
+
+func SetWriteTimeout(d time.Duration) Option {
+	return func(c *Conn) {
+		c.writeTimeout = d
+	}
+}
+
+func SetRetryInterval(d time.Duration) Option {
+	return func(c *Conn) {
+		c.retryInterval = d
+	}
+}
+
+func SetSyncTimeout(d time.Duration) Option {
+	return func(c *Conn) {
+		c.syncTimeout = d
+	}
+}
+
+// SetMaxRetries 设置写入失败后的最大重试次数, 超过后丢弃该条数据, 小于 0 时不重试
+func SetMaxRetries(n int) Option {
+	return func(c *Conn) {
+		c.maxRetries = n
+	}
+}
@@ -0,0 +1,45 @@
+package unixsock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOption(t *testing.T) {
+	tests := []struct {
+		opt Option
+		chk func(c *Conn) bool
+	}{
+		{
+			opt: SetBacklog(1),
+			chk: func(c *Conn) bool { return c.backlog == 1 },
+		},
+		{
+			opt: SetDialTimeout(time.Second),
+			chk: func(c *Conn) bool { return c.dialTimeout == time.Second },
+		},
+		{
+			opt: SetWriteTimeout(2 * time.Second),
+			chk: func(c *Conn) bool { return c.writeTimeout == 2*time.Second },
+		},
+		{
+			opt: SetRetryInterval(3 * time.Second),
+			chk: func(c *Conn) bool { return c.retryInterval == 3*time.Second },
+		},
+		{
+			opt: SetSyncTimeout(4 * time.Second),
+			chk: func(c *Conn) bool { return c.syncTimeout == 4*time.Second },
+		},
+		{
+			opt: SetMaxRetries(5),
+			chk: func(c *Conn) bool { return c.maxRetries == 5 },
+		},
+	}
+	for i, tt := range tests {
+		c := &Conn{}
+		tt.opt(c)
+		if !tt.chk(c) {
+			t.Errorf("%d: failed to check", i)
+		}
+	}
+}
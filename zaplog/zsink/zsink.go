@@ -0,0 +1,81 @@
+package zsink
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Rotator 由滚动文件类的输出实现, 返回已滚动的次数
+type Rotator interface {
+	Rotations() int64
+}
+
+// Queuer 由异步输出实现, 返回队列中等待发送的条数及丢弃的条数
+type Queuer interface {
+	QueueDepth() int
+	Dropped() int64
+}
+
+// EntrySink 由需要日志条目信息(如级别)的输出实现, 如 syslog;
+// fields 包含通过 With 添加的字段及本次写入的字段, b 为编码后的日志
+type EntrySink interface {
+	zap.Sink
+	WriteEntry(ent zapcore.Entry, fields []zapcore.Field, b []byte) (int, error)
+}
+
+// WriteEntry 写入日志, s 实现了 EntrySink 时调用 WriteEntry, 否则调用 Write
+func WriteEntry(s zap.Sink, ent zapcore.Entry, fields []zapcore.Field, b []byte) (int, error) {
+	if es, ok := s.(EntrySink); ok {
+		return es.WriteEntry(ent, fields, b)
+	}
+	return s.Write(b)
+}
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]func(*url.URL) (zap.Sink, error))
+)
+
+// RegisterSink 注册输出, 同时注册到 zap;
+// 通过 Open 打开时返回输出本身, 以便访问 Rotator 等可选接口
+func RegisterSink(scheme string, factory func(*url.URL) (zap.Sink, error)) error {
+	if err := zap.RegisterSink(scheme, factory); err != nil {
+		return err
+	}
+	mu.Lock()
+	factories[strings.ToLower(scheme)] = factory
+	mu.Unlock()
+	return nil
+}
+
+// Open 打开 rawURL 指定的输出, 未通过 RegisterSink 注册的 scheme 交由 zap.Open 处理
+func Open(rawURL string) (zap.Sink, error) {
+	if u, err := url.Parse(rawURL); err == nil && u.Scheme != "" {
+		mu.RLock()
+		factory, ok := factories[strings.ToLower(u.Scheme)]
+		mu.RUnlock()
+		if ok {
+			return factory(u)
+		}
+	}
+
+	ws, closef, err := zap.Open(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &sink{WriteSyncer: ws, closef: closef}, nil
+}
+
+type sink struct {
+	zapcore.WriteSyncer
+	closef func()
+}
+
+func (s *sink) Close() error {
+	s.closef()
+	return nil
+}
@@ -0,0 +1,43 @@
+package zsink
+
+import (
+	"testing"
+
+	"github.com/ironzhang/tlog/zaplog/zsink/rollfile"
+)
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		url   string
+		err   string
+		check func(s interface{}) bool
+	}{
+		{
+			url:   "rfile://workdir/testdata/open.log",
+			check: func(s interface{}) bool { _, ok := s.(*rollfile.File); return ok },
+		},
+		{
+			url:   "stdout",
+			check: func(s interface{}) bool { _, ok := s.(*sink); return ok },
+		},
+		{
+			url: "unknown://host/path",
+			err: "no sink found",
+		},
+	}
+	for i, tt := range tests {
+		s, err := Open(tt.url)
+		if !matchError(t, err, tt.err) {
+			t.Errorf("%d: match error: got %v, want %v", i, err, tt.err)
+			continue
+		}
+		if err != nil {
+			t.Logf("%d: open: %v", i, err)
+			continue
+		}
+		if !tt.check(s) {
+			t.Errorf("%d: unexpected sink type %T", i, s)
+		}
+		s.Close()
+	}
+}